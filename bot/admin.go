@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// isAdmin reports whether the author has ADMIN_ROLE_ID or the Administrator permission.
+func isAdmin(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	if roleID := os.Getenv("ADMIN_ROLE_ID"); roleID != "" && hasRole(m.Member, roleID) {
+		return true
+	}
+	perms, err := s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+	return err == nil && perms&discordgo.PermissionAdministrator != 0
+}
+
+// adminChannel is where alerts for staff go, ADMIN_CHANNEL_ID or the main channel.
+func adminChannel() string {
+	if id := os.Getenv("ADMIN_CHANNEL_ID"); id != "" {
+		return id
+	}
+	return channelID
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	alertQueue   []string
+	alertQueueMu sync.Mutex
+)
+
+// gatewayTimeout is how long the gateway may go without a heartbeat ack before alerts fall back
+// to ALERT_WEBHOOK_URL (ALERT_GATEWAY_TIMEOUT seconds, default 60).
+func gatewayTimeout() time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv("ALERT_GATEWAY_TIMEOUT")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Minute
+}
+
+func gatewayDown(s *discordgo.Session) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return !s.DataReady || time.Since(s.LastHeartbeatAck) > gatewayTimeout()
+}
+
+// sendAlert posts a critical alert to channel. When the gateway is down or the post fails and
+// ALERT_WEBHOOK_URL is set, the alert is queued for delivery through the webhook instead.
+func sendAlert(s *discordgo.Session, channel string, content string) {
+	if os.Getenv("ALERT_WEBHOOK_URL") == "" || !gatewayDown(s) {
+		_, err := s.ChannelMessageSend(channel, content)
+		if err == nil || os.Getenv("ALERT_WEBHOOK_URL") == "" {
+			return
+		}
+		fmt.Println("Error sending alert, queueing for webhook:", err)
+	}
+
+	alertQueueMu.Lock()
+	alertQueue = append(alertQueue, content)
+	alertQueueMu.Unlock()
+}
+
+// deliverQueuedAlerts posts queued alerts to ALERT_WEBHOOK_URL, keeping any that fail for the next try.
+func deliverQueuedAlerts() {
+	ticker := time.NewTicker(10 * time.Second)
+	for range ticker.C {
+		alertQueueMu.Lock()
+		pending := alertQueue
+		alertQueue = nil
+		alertQueueMu.Unlock()
+
+		for i, content := range pending {
+			if err := postWebhook(os.Getenv("ALERT_WEBHOOK_URL"), map[string]string{"content": content}); err != nil {
+				fmt.Println("Error delivering alert via webhook:", err)
+				alertQueueMu.Lock()
+				alertQueue = append(pending[i:], alertQueue...)
+				alertQueueMu.Unlock()
+				break
+			}
+		}
+	}
+}
+
+// postWebhook executes a Discord webhook over plain HTTP, independent of the gateway session.
+func postWebhook(url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := http.Client{Timeout: 10 * time.Second}
+	res, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/monitor"
+)
+
+// serveAPI starts the public HTTP API on HTTP_ADDR.
+func serveAPI() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events.json", handleEventsJSON)
+	mux.HandleFunc("/events.rss", handleEventsRSS)
+	mux.HandleFunc("/probe", handleProbeReport)
+	mux.HandleFunc("/status.json", handleStatusJSON)
+	mux.HandleFunc("/season.json", handleSeasonJSON)
+
+	err := http.ListenAndServe(os.Getenv("HTTP_ADDR"), mux)
+	if err != nil {
+		fmt.Println("Error serving HTTP API:", err)
+	}
+}
+
+func handleEventsJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	json.NewEncoder(w).Encode(recentEvents())
+}
+
+const statusCacheTTL = 30 * time.Second
+
+// PublicStatus is the unauthenticated server status served to the website.
+type PublicStatus struct {
+	Online     bool      `json:"online"`
+	Players    int       `json:"players"`
+	MaxPlayers int       `json:"max_players"`
+	Version    string    `json:"version,omitempty"`
+	MOTD       string    `json:"motd,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+var publicStatusCache = newTTLCache[PublicStatus](statusCacheTTL)
+
+// handleStatusJSON serves the server status, pinging the server at most once per statusCacheTTL.
+func handleStatusJSON(w http.ResponseWriter, r *http.Request) {
+	status, _ := publicStatusCache.get("", func() (PublicStatus, error) {
+		status := PublicStatus{UpdatedAt: time.Now()}
+		if ping, err := monitor.Ping(minecraftAddress()); err == nil {
+			status.Online = true
+			status.Players = ping.Players.Online
+			status.MaxPlayers = ping.Players.Max
+			status.Version = ping.Version.Name
+			status.MOTD = ping.MOTD()
+		}
+		return status, nil
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusCacheTTL.Seconds())))
+	json.NewEncoder(w).Encode(status)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title    string `xml:"title"`
+	Category string `xml:"category"`
+	GUID     string `xml:"guid"`
+	PubDate  string `xml:"pubDate"`
+}
+
+func handleEventsRSS(w http.ResponseWriter, r *http.Request) {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       serverName() + " events",
+			Link:        "https://" + r.Host + "/events.rss",
+			Description: "Recent deaths, joins and restarts on " + serverName(),
+		},
+	}
+	for _, event := range recentEvents() {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:    event.Message,
+			Category: event.Type,
+			GUID:     fmt.Sprintf("%s-%d", event.Type, event.Time.UnixNano()),
+			PubDate:  event.Time.Format(time.RFC1123Z),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(feed)
+}
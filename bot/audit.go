@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// channelNeeds lists the permissions each configured channel needs beyond viewing and sending.
+var channelNeeds = []struct {
+	Env   string
+	Perms int64
+}{
+	{"DISCORD_CHANNEL_ID", discordgo.PermissionReadMessageHistory},
+	{"ADMIN_CHANNEL_ID", discordgo.PermissionCreatePublicThreads | discordgo.PermissionSendMessagesInThreads | discordgo.PermissionAttachFiles},
+	{"CONSOLE_CHANNEL_ID", discordgo.PermissionCreatePublicThreads | discordgo.PermissionSendMessagesInThreads | discordgo.PermissionManageThreads},
+	{"LEADERBOARD_CHANNEL_ID", discordgo.PermissionManageMessages | discordgo.PermissionReadMessageHistory},
+	{"SERVER_INFO_CHANNEL_ID", discordgo.PermissionManageMessages | discordgo.PermissionReadMessageHistory},
+	{"STATUS_CHANNEL_ID", discordgo.PermissionAttachFiles},
+	{"DIGEST_CHANNEL_ID", 0},
+	{"GRAVEYARD_CHANNEL_ID", 0},
+	{"CHAT_CHANNEL_ID", 0},
+	{"DEATHS_CHANNEL_ID", discordgo.PermissionCreatePublicThreads | discordgo.PermissionSendMessagesInThreads | discordgo.PermissionAddReactions},
+	{"MOD_LOG_CHANNEL_ID", 0},
+	{"STARBOARD_CHANNEL_ID", discordgo.PermissionEmbedLinks},
+	{"RULES_CHANNEL_ID", discordgo.PermissionAddReactions | discordgo.PermissionReadMessageHistory},
+}
+
+// managedRoles are the roles the bot assigns, which must sit below its highest role.
+var managedRoles = []string{"PLAYER_ROLE_ID", "BANNED_ROLE_ID"}
+
+// auditPermissions checks the bot's own permissions in every configured channel and its place
+// in the role hierarchy, and reports anything missing to the admin channel.
+func auditPermissions(s *discordgo.Session) {
+	var problems []string
+	guilds := map[string]bool{}
+
+	check := func(name string, id string, needs int64) {
+		channel, err := s.Channel(id)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s (%s): cannot access channel: %s", name, id, err))
+			return
+		}
+		guilds[channel.GuildID] = true
+
+		perms, err := s.UserChannelPermissions(s.State.User.ID, id)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s <#%s>: cannot compute permissions: %s", name, id, err))
+			return
+		}
+		needs |= discordgo.PermissionViewChannel | discordgo.PermissionSendMessages
+		if missing := needs &^ perms; missing != 0 {
+			problems = append(problems, fmt.Sprintf("%s <#%s>: missing %s", name, id, formatPermissions(missing)))
+		}
+	}
+
+	for _, need := range channelNeeds {
+		if id := os.Getenv(need.Env); id != "" {
+			check(need.Env, id, need.Perms)
+		}
+	}
+	for guildID, id := range guildChannels {
+		check("GUILD_CHANNELS "+guildID, id, 0)
+	}
+
+	for guildID := range guilds {
+		problems = append(problems, auditRoles(s, guildID)...)
+	}
+
+	if len(problems) == 0 {
+		fmt.Println("Permission audit passed")
+		return
+	}
+	fmt.Println("Permission audit found problems:\n" + strings.Join(problems, "\n"))
+	s.ChannelMessageSend(adminChannel(), "**PERMISSION AUDIT**:\n"+strings.Join(problems, "\n"))
+}
+
+// auditRoles checks that the bot can manage the roles it assigns in guildID.
+func auditRoles(s *discordgo.Session, guildID string) []string {
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return []string{"Cannot fetch guild " + guildID + ": " + err.Error()}
+	}
+	member, err := s.GuildMember(guildID, s.State.User.ID)
+	if err != nil {
+		return []string{"Cannot fetch own member in " + guild.Name + ": " + err.Error()}
+	}
+
+	positions := map[string]*discordgo.Role{}
+	var perms int64
+	for _, role := range guild.Roles {
+		positions[role.ID] = role
+		if role.ID == guildID || hasRole(member, role.ID) {
+			perms |= role.Permissions
+		}
+	}
+	top := 0
+	for _, id := range member.Roles {
+		if role, ok := positions[id]; ok && role.Position > top {
+			top = role.Position
+		}
+	}
+
+	var problems []string
+	for _, env := range managedRoles {
+		role, ok := positions[os.Getenv(env)]
+		if !ok {
+			continue
+		}
+		if perms&(discordgo.PermissionManageRoles|discordgo.PermissionAdministrator) == 0 {
+			problems = append(problems, fmt.Sprintf("%s @%s: bot lacks Manage Roles in %s", env, role.Name, guild.Name))
+		}
+		if role.Position >= top {
+			problems = append(problems, fmt.Sprintf("%s @%s: role is above the bot's highest role, move the bot's role higher", env, role.Name))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/commands"
+)
+
+const autoRestartWarning = 5 * time.Minute
+
+// AutoRestartPolicy restarts the server once TPS or memory has been degraded for Duration.
+type AutoRestartPolicy struct {
+	MinTPS   float64
+	MaxRSSMB int
+	Duration time.Duration
+}
+
+func loadAutoRestartPolicy() (AutoRestartPolicy, error) {
+	policy := AutoRestartPolicy{Duration: 10 * time.Minute}
+
+	var err error
+	if raw := os.Getenv("AUTO_RESTART_MIN_TPS"); raw != "" {
+		if policy.MinTPS, err = strconv.ParseFloat(raw, 64); err != nil {
+			return policy, fmt.Errorf("invalid AUTO_RESTART_MIN_TPS: %w", err)
+		}
+	}
+	if raw := os.Getenv("AUTO_RESTART_MAX_RSS_MB"); raw != "" {
+		if policy.MaxRSSMB, err = strconv.Atoi(raw); err != nil {
+			return policy, fmt.Errorf("invalid AUTO_RESTART_MAX_RSS_MB: %w", err)
+		}
+	}
+	if raw := os.Getenv("AUTO_RESTART_MINUTES"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil {
+			return policy, fmt.Errorf("invalid AUTO_RESTART_MINUTES: %w", err)
+		}
+		policy.Duration = time.Duration(minutes) * time.Minute
+	}
+	return policy, nil
+}
+
+// watchServerHealth checks the policy every minute and performs a graceful restart when it trips.
+func watchServerHealth(s *discordgo.Session, policy AutoRestartPolicy) {
+	var degradedSince time.Time
+	ticker := time.NewTicker(time.Minute)
+	for range ticker.C {
+		reason := policy.check()
+		if reason == "" {
+			degradedSince = time.Time{}
+			continue
+		}
+		if degradedSince.IsZero() {
+			degradedSince = time.Now()
+		}
+		if time.Since(degradedSince) < policy.Duration {
+			continue
+		}
+
+		reason = fmt.Sprintf("%s for %s", reason, policy.Duration)
+		sendAlert(s, channelID, "**AUTO RESTART**: "+reason)
+		gracefulRestart(s, reason)
+		degradedSince = time.Time{}
+	}
+}
+
+// check returns why the server is degraded, or "" if it is healthy or not running.
+func (p AutoRestartPolicy) check() string {
+	stats, err := controller.Stats(context.Background())
+	if err != nil || !stats.Running {
+		return ""
+	}
+
+	if p.MinTPS > 0 {
+		tps, err := readTPS()
+		if err == nil && tps < p.MinTPS {
+			return fmt.Sprintf("TPS %.1f below %.1f", tps, p.MinTPS)
+		}
+	}
+	if p.MaxRSSMB > 0 {
+		if rss := stats.MemoryKB / 1024; rss > p.MaxRSSMB {
+			return fmt.Sprintf("RSS %d MB above %d MB", rss, p.MaxRSSMB)
+		}
+	}
+	return ""
+}
+
+// gracefulRestart warns online players and counts down before restarting. Empty servers restart immediately.
+// Admins can abort the countdown with `cancel restart`.
+func gracefulRestart(s *discordgo.Session, reason string) {
+	ctx, done, err := startTask("restart")
+	if err != nil {
+		fmt.Println("Skipping restart:", err)
+		return
+	}
+	defer done()
+
+	notifySubscribers(s, "The Minecraft server is restarting automatically: "+reason)
+	if players, _, err := onlinePlayers(); err == nil && len(players) > 0 {
+		for remaining := autoRestartWarning; remaining > 0; remaining -= time.Minute {
+			rconExecute(fmt.Sprintf("say Server restarting in %d minute(s) due to degraded performance", int(remaining.Minutes())))
+			select {
+			case <-time.After(time.Minute):
+			case <-ctx.Done():
+				rconExecute("say Restart cancelled.")
+				s.ChannelMessageSend(channelID, "**AUTO RESTART**: "+taskError(ctx, "restart"))
+				return
+			}
+		}
+	}
+
+	appendRestart(Restart{Time: time.Now(), Action: "auto-restart", Username: "auto", Reason: reason})
+	markExpectedStop()
+	closeRcon()
+	commands.Restart(ctx, s, channelID, serverCommands())
+}
+
+// processRSS reads the resident set size of a process in kB.
+func processRSS(pid int) (int, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// VmRSS:	 1234567 kB
+		if value, found := strings.CutPrefix(scanner.Text(), "VmRSS:"); found {
+			return strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), " kB"))
+		}
+	}
+	return 0, fmt.Errorf("VmRSS not found for pid %d", pid)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache memoizes the results of expensive reads for ttl, so features that poll the same
+// data don't each repeat the RCON, file or Discord calls behind it. Errors are not cached.
+type ttlCache[T any] struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry[T]
+}
+
+type cacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+func newTTLCache[T any](ttl time.Duration) *ttlCache[T] {
+	return &ttlCache[T]{ttl: ttl, entries: map[string]cacheEntry[T]{}}
+}
+
+// get returns the cached value for key, calling load when it is missing or expired.
+// The lock is held while loading so concurrent callers share one load.
+func (c *ttlCache[T]) get(key string, load func() (T, error)) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok && time.Now().Before(entry.expires) {
+		return entry.value, nil
+	}
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.entries[key] = cacheEntry[T]{value: value, expires: time.Now().Add(c.ttl)}
+	return value, nil
+}
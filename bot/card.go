@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	cardWidth   = 480
+	cardPadding = 16
+	faceSize    = 24
+	rowHeight   = 32
+)
+
+var (
+	cardBackground = color.RGBA{0x2b, 0x2d, 0x31, 0xff}
+	cardText       = color.RGBA{0xf2, 0xf3, 0xf5, 0xff}
+	cardMuted      = color.RGBA{0x94, 0x9b, 0xa4, 0xff}
+	cardGreen      = color.RGBA{0x57, 0xf2, 0x87, 0xff}
+	cardRed        = color.RGBA{0xed, 0x42, 0x45, 0xff}
+)
+
+var faceClient = &http.Client{Timeout: 5 * time.Second}
+
+// renderStatusCard draws the server status as a PNG.
+func renderStatusCard(status ServerStatus) ([]byte, error) {
+	height := cardPadding*2 + rowHeight*3 + rowHeight*len(status.Players)
+	if theme.Footer != "" {
+		height += rowHeight
+	}
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{cardBackground}, image.Point{}, draw.Src)
+
+	y := cardPadding
+	drawText(img, cardPadding, y, status.Name, 3, cardText)
+	state, stateColor := "OFFLINE", cardRed
+	if status.Online {
+		state, stateColor = "ONLINE", cardGreen
+	}
+	drawText(img, cardWidth-cardPadding-textWidth(state, 2), y+4, state, 2, stateColor)
+
+	y += rowHeight + 8
+	stats := fmt.Sprintf("TPS: %.1f   UPTIME: %s", status.TPS, formatUptime(status.Uptime))
+	drawText(img, cardPadding, y, stats, 2, cardMuted)
+
+	y += rowHeight
+	drawText(img, cardPadding, y, fmt.Sprintf("PLAYERS: %d/%d", len(status.Players), status.MaxPlayers), 2, cardMuted)
+
+	for _, player := range status.Players {
+		y += rowHeight
+		if face, err := fetchFace(player); err == nil {
+			draw.Draw(img, image.Rect(cardPadding, y, cardPadding+faceSize, y+faceSize), face, face.Bounds().Min, draw.Over)
+		}
+		drawText(img, cardPadding+faceSize+12, y+5, player, 2, cardText)
+	}
+	if theme.Footer != "" {
+		drawText(img, cardWidth-cardPadding-textWidth(theme.Footer, 1), height-cardPadding-7, theme.Footer, 1, cardMuted)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fetchFace downloads the player's skin face at faceSize pixels.
+func fetchFace(player string) (image.Image, error) {
+	resp, err := faceClient.Get(fmt.Sprintf("https://mc-heads.net/avatar/%s/%d.png", player, faceSize))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching face: %s", resp.Status)
+	}
+	return png.Decode(resp.Body)
+}
+
+func formatUptime(uptime time.Duration) string {
+	hours := int(uptime.Hours())
+	return fmt.Sprintf("%dH %02dM", hours, int(uptime.Minutes())-hours*60)
+}
+
+func sendStatusCard(s *discordgo.Session, targetChannelID string) {
+	card, err := renderStatusCard(readServerStatus())
+	if err != nil {
+		s.ChannelMessageSend(targetChannelID, "Failed to render status card: "+err.Error())
+		return
+	}
+	_, err = s.ChannelFileSend(targetChannelID, "status.png", bytes.NewReader(card))
+	if err != nil {
+		fmt.Println("Error sending status card:", err)
+	}
+}
+
+// postStatusCards posts a status card to STATUS_CHANNEL_ID every STATUS_CARD_INTERVAL.
+func postStatusCards(s *discordgo.Session) {
+	interval, err := time.ParseDuration(os.Getenv("STATUS_CARD_INTERVAL"))
+	if err != nil {
+		fmt.Println("Invalid STATUS_CARD_INTERVAL:", err)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		sendStatusCard(s, os.Getenv("STATUS_CHANNEL_ID"))
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// [12:34:56] [Server thread/INFO]: [Not Secure] <Steve> hello
+var chatRegex = regexp.MustCompile(`^\[[\d:]+\] \[Server thread/INFO\]: (?:\[Not Secure\] )?<(\w{3,16})> (.*)$`)
+
+// parseChatLine extracts the player and message from an in-game chat log line.
+func parseChatLine(line string) (player string, message string, ok bool) {
+	match := chatRegex.FindStringSubmatch(line)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// relayChat posts filtered in-game chat to CHAT_CHANNEL_ID, with an inline translation when translation is configured.
+// Translation can take seconds, so the log relay runs it on a lineWorker.
+// With CHAT_WEBHOOK_URL (a webhook in that channel) each message shows the player's name and face.
+func relayChat(s *discordgo.Session, line string) {
+	chatChannelID := os.Getenv("CHAT_CHANNEL_ID")
+	if chatChannelID == "" {
+		return
+	}
+	player, message, ok := parseChatLine(line)
+	if !ok {
+		return
+	}
+	message = filterChat(chatChannelID, message)
+	content := message + translateInline(message)
+
+	// Post as the player through CHAT_WEBHOOK_URL when configured. Players can type @everyone
+	// too, so never let relayed chat ping.
+	if webhookURL := os.Getenv("CHAT_WEBHOOK_URL"); webhookURL != "" {
+		err := postWebhook(webhookURL, map[string]any{
+			"username":         player,
+			"avatar_url":       fmt.Sprintf("https://mc-heads.net/avatar/%s/64.png", player),
+			"content":          content,
+			"allowed_mentions": map[string][]string{"parse": {}},
+		})
+		if err == nil {
+			return
+		}
+		fmt.Println("Error relaying chat through webhook:", err)
+	}
+	s.ChannelMessageSendComplex(chatChannelID, &discordgo.MessageSend{
+		Content:         fmt.Sprintf("**%s**: %s", player, content),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+}
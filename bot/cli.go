@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/moderation"
+	"github.com/joho/godotenv"
+)
+
+// stores are the JSON files the bot keeps state in, with the type each one decodes into.
+var stores = []struct {
+	Name string
+	Path func() string
+	New  func() any
+}{
+	{"coords", coordsFile, func() any { return &[]Coord{} }},
+	{"metrics", metricsFile, func() any { return &MetricsStore{} }},
+	{"restarts", restartsFile, func() any { return &[]Restart{} }},
+	{"notify", notifyFile, func() any { return &map[string]bool{} }},
+	{"logpos", logPositionFile, func() any { return &LogPosition{} }},
+	{"cases", casesFile, func() any { return &[]moderation.Case{} }},
+	{"rules", rulesAckFile, func() any { return &map[string]time.Time{} }},
+	{"starboard", starboardFile, func() any { return &map[string]string{} }},
+	{"death threads", deathThreadsFile, func() any { return &map[string]string{} }},
+}
+
+// runSelftest checks the configuration, Discord, RCON and the stores, and reports whether all passed.
+func runSelftest() bool {
+	ok := true
+	check := func(name string, err error) {
+		if err != nil {
+			fmt.Printf("FAIL %s: %s\n", name, err)
+			ok = false
+			return
+		}
+		fmt.Println("OK   " + name)
+	}
+
+	_, process := controller.(*processController)
+	keys := []string{"DISCORD_TOKEN", "DISCORD_CHANNEL_ID", "COMMAND_PREFIX", "RCON_IP"}
+	if process {
+		keys = append(keys, "START_COMMAND")
+	}
+	for _, key := range keys {
+		var err error
+		if os.Getenv(key) == "" {
+			err = fmt.Errorf("not set")
+		}
+		check(key, err)
+	}
+
+	dg, err := discordgo.New("Bot " + os.Getenv("DISCORD_TOKEN"))
+	if err == nil {
+		_, err = dg.User("@me")
+	}
+	check("discord login", err)
+	if err == nil {
+		_, err = dg.Channel(channelID)
+		check("discord channel", err)
+	}
+
+	_, err = rconExecute("list")
+	check("rcon", err)
+	closeRcon()
+
+	// Only the process backend runs the server here; the others are reached through their API
+	if process {
+		_, err = os.Stat("../server/server.out")
+		check("server log", err)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err = controller.Stats(ctx)
+		cancel()
+		check("server backend "+os.Getenv("SERVER_BACKEND"), err)
+	}
+
+	for _, store := range stores {
+		check("store "+store.Name, readJSONFile(store.Path(), store.New()))
+	}
+	return ok
+}
+
+// migrateStores rewrites every existing store in the current format, failing on any that no longer decode.
+func migrateStores() error {
+	for _, store := range stores {
+		path := store.Path()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		v := store.New()
+		if err := readJSONFile(path, v); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := writeJSONFile(path, v, fileBackups()); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Println("Migrated", path)
+	}
+	return nil
+}
+
+// exportConfig writes the effective value of every variable in ../.env, with secrets redacted.
+func exportConfig(w io.Writer) {
+	env, err := godotenv.Read("../.env")
+	if err != nil {
+		fmt.Println("Error reading .env file:", err)
+		return
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := os.Getenv(key)
+		if isSecret(key) && value != "" {
+			value = "<redacted>"
+		}
+		fmt.Fprintf(w, "%s=%s\n", key, value)
+	}
+}
+
+func isSecret(key string) bool {
+	for _, word := range []string{"TOKEN", "PW", "PASSWORD", "KEY", "SECRET"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
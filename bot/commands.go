@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/commands"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/moderation"
+)
+
+// commandRouter routes prefix commands. It is nil when message content is disabled.
+var commandRouter *mcbot.Router
+
+// serverCommands configures the shared server commands: stop and restart take a reason, run
+// as tasks and are recorded in the restart history.
+func serverCommands() commands.Options {
+	return commands.Options{
+		Server:        controller,
+		RequireReason: true,
+		Begin: func(c *mcbot.Context, action string, reason string) (context.Context, func(), error) {
+			ctx, done, err := startTask(action)
+			if err != nil {
+				return nil, nil, err
+			}
+			recordRestart(c.Message, action, reason)
+			if action == "stop" {
+				notifySubscribers(c.Session, "The Minecraft server is being stopped: "+reason)
+			} else {
+				notifySubscribers(c.Session, "The Minecraft server is restarting: "+reason)
+			}
+			markExpectedStop()
+			closeRcon()
+			return ctx, done, nil
+		},
+	}
+}
+
+// newCommandRouter registers the bot's prefix commands on top of the shared server and
+// moderation commands. Anything not listed here is run on the server over rcon.
+func newCommandRouter() *mcbot.Router {
+	r := mcbot.NewRouter(commandPrefix)
+	r.Allow = isCommandChannel
+	commands.Register(r, serverCommands())
+	r.NotFound = func(c *mcbot.Context) { executeRcon(c.Session, c.Message, c.Raw) }
+
+	moderator, err := moderation.New(moderation.Options{
+		Console:      rconExecute,
+		IsAdmin:      func(c *mcbot.Context) bool { return isAdmin(c.Session, c.Message) },
+		Store:        caseStore{},
+		LogChannelID: modLogChannel(),
+		BannedRoleID: os.Getenv("BANNED_ROLE_ID"),
+	})
+	if err != nil {
+		fmt.Println("Error configuring moderation:", err)
+	} else {
+		moderator.Register(r)
+	}
+
+	// `status fancy` shows the status card instead
+	status := r.Handler("status")
+	r.Handle(func(c *mcbot.Context) {
+		if len(c.Args) > 0 && c.Args[0] == "fancy" {
+			sendStatusCard(c.Session, c.Message.ChannelID)
+			return
+		}
+		status(c)
+	}, "status")
+	r.Handle(func(c *mcbot.Context) { c.Reply(ReadMemoryStats().ToStr()) }, "mem")
+
+	r.Handle(plainCommand(showRestartHistory), "restarts")
+	r.Handle(plainCommand(showDNSStatus), "dns")
+	r.Handle(plainCommand(showProbes), "probes")
+	r.Handle(plainCommand(handleTranslate), "translate")
+	r.Handle(plainCommand(showBans), "bans")
+
+	r.Handle(argsCommand(handleCoords), "coords")
+	r.Handle(argsCommand(handleJVM), "jvm")
+	r.Handle(argsCommand(handleNotify), "notify")
+	r.Handle(argsCommand(handlePrune), "prune")
+	r.Handle(argsCommand(handlePerms), "perms")
+	r.Handle(argsCommand(handleCancel), "cancel")
+	r.Handle(argsCommand(handleWhois), "whois")
+	r.Handle(argsCommand(handleRules), "rules")
+	r.Handle(argsCommand(handleLagspots), "lagspots")
+	r.Handle(argsCommand(handleMsg), "msg")
+	r.Handle(argsCommand(handleGamerule), "gamerule")
+	r.Handle(argsCommand(handleQOL), "qol")
+	return r
+}
+
+func plainCommand(handler func(s *discordgo.Session, m *discordgo.MessageCreate)) mcbot.HandlerFunc {
+	return func(c *mcbot.Context) { handler(c.Session, c.Message) }
+}
+
+func argsCommand(handler func(s *discordgo.Session, m *discordgo.MessageCreate, args []string)) mcbot.HandlerFunc {
+	return func(c *mcbot.Context) { handler(c.Session, c.Message, c.Args) }
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/docker"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/pterodactyl"
+)
+
+// controller is the configured backend, set up by loadConfig.
+var controller mcbot.Server
+
+// newServerController creates the backend named by SERVER_BACKEND, "process" by default.
+func newServerController() (mcbot.Server, error) {
+	switch backend := os.Getenv("SERVER_BACKEND"); backend {
+	case "", "process":
+		return &processController{dir: "../server", logFile: "server.out"}, nil
+	case "pterodactyl":
+		if os.Getenv("PTERODACTYL_URL") == "" || os.Getenv("PTERODACTYL_SERVER_ID") == "" {
+			return nil, fmt.Errorf("PTERODACTYL_URL and PTERODACTYL_SERVER_ID must be set")
+		}
+		return pterodactyl.New(pterodactyl.Options{
+			URL:      os.Getenv("PTERODACTYL_URL"),
+			APIKey:   os.Getenv("PTERODACTYL_API_KEY"),
+			ServerID: os.Getenv("PTERODACTYL_SERVER_ID"),
+		})
+	case "docker":
+		return docker.New(docker.Options{Host: os.Getenv("DOCKER_HOST"), Container: os.Getenv("DOCKER_CONTAINER")})
+	default:
+		return nil, fmt.Errorf("unknown SERVER_BACKEND %q", backend)
+	}
+}
+
+// serverRunning asks the backend whether the server is up. Errors count as down.
+func serverRunning() bool {
+	return mcbot.Running(context.Background(), controller)
+}
+
+// processController supervises the server as a child process on this machine, started with
+// START_COMMAND and logging to a file the relay tails.
+type processController struct {
+	dir     string
+	logFile string
+}
+
+func (p *processController) Start(ctx context.Context) error {
+	if os.Getenv("START_COMMAND") == "" {
+		return fmt.Errorf("START_COMMAND is not set")
+	}
+
+	cmdArgs := strings.Fields(os.Getenv("START_COMMAND"))
+	cmd := exec.Command("nohup", cmdArgs...)
+	cmd.Dir = p.dir
+
+	// Redirect output to the log file
+	stdout, err := os.Create(filepath.Join(p.dir, p.logFile))
+	if err != nil {
+		return fmt.Errorf("create log file: %w", err)
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stdout
+	return cmd.Start()
+}
+
+func (p *processController) Stop(ctx context.Context) error {
+	return exec.CommandContext(ctx, "pkill", "-f", "server.jar").Run()
+}
+
+func (p *processController) Restart(ctx context.Context) error {
+	if err := p.Stop(ctx); err != nil {
+		return err
+	}
+	if err := p.waitForExit(ctx, time.Minute); err != nil {
+		return err
+	}
+	return p.Start(ctx)
+}
+
+func (p *processController) waitForExit(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := serverPID(); err != nil {
+			return nil
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("server did not exit within %s", timeout)
+}
+
+func (p *processController) Stats(ctx context.Context) (mcbot.ServerStats, error) {
+	pid, err := serverPID()
+	if err != nil {
+		return mcbot.ServerStats{}, nil
+	}
+	stats := mcbot.ServerStats{Running: true}
+	if stats.Uptime, err = processUptime(pid); err != nil {
+		return stats, err
+	}
+	stats.MemoryKB, err = processRSS(pid)
+	return stats, err
+}
+
+func (p *processController) SendCommand(ctx context.Context, cmd string) (string, error) {
+	return rconExecute(cmd)
+}
+
+// Logs tails the log file from where the relay left off before the bot restarted.
+func (p *processController) Logs(ctx context.Context) (<-chan string, error) {
+	path := filepath.Join(p.dir, p.logFile)
+	lines := make(chan string, 256)
+	go func() {
+		defer close(lines)
+
+		offset, skipped := restoreLogPosition(path)
+		if skipped > 0 {
+			lines <- fmt.Sprintf("[xn-mc] Skipped %d KB of server log written while the bot was down.", skipped/1024)
+		}
+
+		ticker := time.NewTicker(4 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			offset = p.readLines(path, offset, lines)
+		}
+	}()
+	return lines, nil
+}
+
+// readLines sends the lines written after offset and returns the new offset.
+func (p *processController) readLines(path string, offset int64, lines chan<- string) int64 {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Println("Error opening log file:", err)
+		return offset
+	}
+	defer file.Close()
+	offset = checkLogRotation(file, offset)
+
+	// Seek to the last read position
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		fmt.Println("Error seeking log file:", err)
+		return offset
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines <- scanner.Text()
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Println("Error reading log file:", err)
+		return offset
+	}
+
+	position, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		fmt.Println("Error getting current position in log file:", err)
+		return offset
+	}
+	if position != offset {
+		saveLogPosition(position)
+	}
+	return position
+}
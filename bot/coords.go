@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Coord is a bookmarked location owned by either a user or a role.
+type Coord struct {
+	Name    string
+	X, Y, Z int
+	OwnerID string
+	IsRole  bool
+}
+
+var coordsMu sync.Mutex
+
+func coordsFile() string {
+	if path := os.Getenv("COORDS_FILE"); path != "" {
+		return path
+	}
+	return "coords.json"
+}
+
+func handleCoords(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	usage := "Usage: `coords save <name> <x> <y> <z> [@role]`, `coords list`, `coords export @role [map]`"
+	if len(args) == 0 {
+		s.ChannelMessageSend(m.ChannelID, usage)
+		return
+	}
+
+	switch args[0] {
+	case "save":
+		saveCoord(s, m, args[1:])
+	case "list":
+		listCoords(s, m)
+	case "export":
+		exportCoords(s, m, args[1:])
+	default:
+		s.ChannelMessageSend(m.ChannelID, usage)
+	}
+}
+
+func saveCoord(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) < 4 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `coords save <name> <x> <y> <z> [@role]`")
+		return
+	}
+
+	var xyz [3]int
+	for i, raw := range args[1:4] {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid coordinate %q", raw))
+			return
+		}
+		xyz[i] = value
+	}
+
+	coord := Coord{Name: args[0], X: xyz[0], Y: xyz[1], Z: xyz[2], OwnerID: m.Author.ID}
+	if len(m.MentionRoles) > 0 {
+		roleID := m.MentionRoles[0]
+		if !hasRole(m.Member, roleID) {
+			s.ChannelMessageSend(m.ChannelID, "You can only save coords for a role you have.")
+			return
+		}
+		coord.OwnerID = roleID
+		coord.IsRole = true
+	}
+
+	coordsMu.Lock()
+	defer coordsMu.Unlock()
+
+	var coords []Coord
+	if err := readJSONFile(coordsFile(), &coords); err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read coords: "+err.Error())
+		return
+	}
+
+	// Saving under an existing name overwrites it
+	replaced := false
+	for i, existing := range coords {
+		if existing.Name == coord.Name && existing.OwnerID == coord.OwnerID {
+			coords[i] = coord
+			replaced = true
+		}
+	}
+	if !replaced {
+		coords = append(coords, coord)
+	}
+
+	if err := writeJSONFile(coordsFile(), coords, fileBackups()); err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to save coords: "+err.Error())
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Saved %s at %d %d %d.", coord.Name, coord.X, coord.Y, coord.Z))
+}
+
+// listCoords shows the author's own coords and those of every role they have.
+func listCoords(s *discordgo.Session, m *discordgo.MessageCreate) {
+	coordsMu.Lock()
+	var coords []Coord
+	err := readJSONFile(coordsFile(), &coords)
+	coordsMu.Unlock()
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read coords: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	for _, coord := range coords {
+		owner := "you"
+		if coord.IsRole {
+			if !hasRole(m.Member, coord.OwnerID) {
+				continue
+			}
+			owner = "<@&" + coord.OwnerID + ">"
+		} else if coord.OwnerID != m.Author.ID {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s: %d %d %d (%s)\n", coord.Name, coord.X, coord.Y, coord.Z, owner)
+	}
+
+	if sb.Len() == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No coords saved.")
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, "COORDS:\n"+sb.String())
+}
+
+// exportCoords sends a role's coords as a BlueMap marker set, ready to paste into a map config.
+func exportCoords(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(m.MentionRoles) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `coords export @role [map]`")
+		return
+	}
+	roleID := m.MentionRoles[0]
+	if !hasRole(m.Member, roleID) {
+		s.ChannelMessageSend(m.ChannelID, "You can only export coords for a role you have.")
+		return
+	}
+	mapID := "world"
+	if len(args) > 1 {
+		mapID = args[1]
+	}
+
+	coordsMu.Lock()
+	var coords []Coord
+	err := readJSONFile(coordsFile(), &coords)
+	coordsMu.Unlock()
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read coords: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "marker-sets: {\n  team-%s: {\n    label: \"Team Coords\"\n    markers: {\n", roleID)
+	for _, coord := range coords {
+		if !coord.IsRole || coord.OwnerID != roleID {
+			continue
+		}
+		fmt.Fprintf(&sb, "      %q: {\n        type: \"poi\"\n        position: { x: %d, y: %d, z: %d }\n        label: %q\n      }\n",
+			coord.Name, coord.X, coord.Y, coord.Z, coord.Name)
+	}
+	sb.WriteString("    }\n  }\n}\n")
+
+	_, err = s.ChannelFileSendWithMessage(m.ChannelID, "BlueMap markers for <@&"+roleID+">", mapID+".conf", strings.NewReader(sb.String()))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to export coords: "+err.Error())
+	}
+}
+
+func hasRole(member *discordgo.Member, roleID string) bool {
+	if member == nil {
+		return false
+	}
+	for _, id := range member.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/dns"
+	"github.com/hunterjsb/xn-mc/pkg/statuspage"
+)
+
+func newDNSProvider() (dns.Provider, error) {
+	switch provider := os.Getenv("DDNS_PROVIDER"); provider {
+	case "cloudflare":
+		return &dns.Cloudflare{
+			Token:    os.Getenv("CLOUDFLARE_API_TOKEN"),
+			ZoneID:   os.Getenv("CLOUDFLARE_ZONE_ID"),
+			RecordID: os.Getenv("CLOUDFLARE_RECORD_ID"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported DDNS_PROVIDER %q", provider)
+	}
+}
+
+// runDDNS updates DNS_HOSTNAME whenever the public IP changes and announces it to admins.
+// While the new record propagates, a Statuspage incident is kept open if Statuspage is configured.
+func runDDNS(s *discordgo.Session, provider dns.Provider) {
+	var incidentID string
+	dns.RunDDNS(context.Background(), dns.DDNSOptions{
+		Options:  dnsOptions(),
+		Provider: provider,
+		OnUpdate: func(old net.IP, ip net.IP, propagated bool) {
+			s.ChannelMessageSend(adminChannel(), fmt.Sprintf("**DDNS**: public IP changed from %s to %s, updated %s.", old, ip, dnsHostname()))
+			if !propagated {
+				incidentID = openDNSIncident()
+			}
+		},
+		OnPropagated: func(ip net.IP) {
+			if incidentID != "" {
+				resolveDNSIncident(incidentID)
+				incidentID = ""
+			}
+		},
+		OnError: func(ip net.IP, err error) {
+			sendAlert(s, adminChannel(), fmt.Sprintf("**DDNS**: failed to point %s at %s: %s", dnsHostname(), ip, err))
+		},
+	})
+}
+
+func openDNSIncident() string {
+	client := statuspageClient()
+	if client == nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	incident, err := client.CreateIncident(ctx, statuspage.IncidentParams{
+		Name:   "Server address changing",
+		Status: statuspage.IncidentIdentified,
+		Body:   fmt.Sprintf("The server's IP address changed and %s is being updated. Some players may not be able to connect until DNS propagates.", dnsHostname()),
+	})
+	if err != nil {
+		fmt.Println("Error creating DNS incident:", err)
+		return ""
+	}
+	return incident.ID
+}
+
+func resolveDNSIncident(incidentID string) {
+	client := statuspageClient()
+	if client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := client.ResolveIncident(ctx, incidentID, dnsHostname()+" has propagated to the new address."); err != nil {
+		fmt.Println("Error resolving DNS incident:", err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Steve has the following entity data: {dimension: "minecraft:overworld", pos: [I; 12, 64, -30]}
+var deathLocationRegex = regexp.MustCompile(`dimension: "(?:minecraft:)?([\w/]+)", pos: \[I; (-?\d+), (-?\d+), (-?\d+)\]`)
+
+var deathThreadsMu sync.Mutex
+
+func deathThreadsFile() string {
+	if path := os.Getenv("DEATH_THREADS_FILE"); path != "" {
+		return path
+	}
+	return "death_threads.json"
+}
+
+// parseDeathLine returns the player and full death message of a death in the server log.
+func parseDeathLine(line string) (player string, message string, ok bool) {
+	match := serverLogRegex.FindStringSubmatch(line)
+	if match == nil {
+		return "", "", false
+	}
+	parts := playerRegex.FindStringSubmatch(match[1])
+	if parts == nil || !isDeathMessage(parts[2]) {
+		return "", "", false
+	}
+	return parts[1], match[1], true
+}
+
+// postDeath appends a death to the dying player's memorial thread in DEATHS_CHANNEL_ID, with
+// where they died and a DEATH_MAP_URL link ({world}, {x}, {y} and {z} are filled in). It makes
+// several slow calls, so the log relay runs it on a lineWorker.
+func postDeath(s *discordgo.Session, line string) {
+	deathsChannelID := os.Getenv("DEATHS_CHANNEL_ID")
+	if deathsChannelID == "" {
+		return
+	}
+	player, message, ok := parseDeathLine(line)
+	if !ok {
+		return
+	}
+
+	content := "**" + message + "**"
+	response, err := rconExecute(fmt.Sprintf("data get entity %s LastDeathLocation", player))
+	if location := deathLocationRegex.FindStringSubmatch(response); err == nil && location != nil {
+		world, x, y, z := location[1], location[2], location[3], location[4]
+		content += fmt.Sprintf("\nAt %s %s %s in the %s", x, y, z, world)
+		if mapURL := os.Getenv("DEATH_MAP_URL"); mapURL != "" {
+			content += "\n" + strings.NewReplacer("{world}", world, "{x}", x, "{y}", y, "{z}", z).Replace(mapURL)
+		}
+	}
+
+	deathThreadsMu.Lock()
+	defer deathThreadsMu.Unlock()
+
+	threads := map[string]string{}
+	if err = readJSONFile(deathThreadsFile(), &threads); err != nil {
+		fmt.Println("Error reading death threads:", err)
+		return
+	}
+
+	var msg *discordgo.Message
+	threadID, ok := threads[player]
+	if ok {
+		msg, err = s.ChannelMessageSend(threadID, content)
+	}
+	if !ok || err != nil {
+		// No thread yet, or it was deleted
+		thread, err := s.ThreadStart(deathsChannelID, player+"'s deaths", discordgo.ChannelTypeGuildPublicThread, 10080)
+		if err != nil {
+			fmt.Println("Error creating death thread:", err)
+			return
+		}
+		threads[player] = thread.ID
+		if err = writeJSONFile(deathThreadsFile(), threads, fileBackups()); err != nil {
+			fmt.Println("Error saving death threads:", err)
+		}
+		if msg, err = s.ChannelMessageSend(thread.ID, content); err != nil {
+			fmt.Println("Error posting death:", err)
+			return
+		}
+	}
+	s.MessageReactionAdd(msg.ChannelID, msg.ID, theme.GraveEmoji)
+}
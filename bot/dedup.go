@@ -0,0 +1,33 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const handledMessageTTL = 10 * time.Minute
+
+var (
+	handledMessages   = map[string]time.Time{}
+	handledMessagesMu sync.Mutex
+)
+
+// firstDelivery reports whether the message with this ID is being handled for the first time.
+// Discord can deliver the same event again around a session resume, and a redelivered
+// `restart` must not restart the server twice. The IDs are only kept in memory; separate
+// restart and stop commands are kept from overlapping by startTask.
+func firstDelivery(messageID string) bool {
+	handledMessagesMu.Lock()
+	defer handledMessagesMu.Unlock()
+
+	for id, handled := range handledMessages {
+		if time.Since(handled) > handledMessageTTL {
+			delete(handledMessages, id)
+		}
+	}
+	if _, seen := handledMessages[messageID]; seen {
+		return false
+	}
+	handledMessages[messageID] = time.Now()
+	return true
+}
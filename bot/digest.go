@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// postWeeklyDigests posts a digest of the past week to DIGEST_CHANNEL_ID every Monday at midnight.
+func postWeeklyDigests(s *discordgo.Session) {
+	for {
+		time.Sleep(time.Until(nextMonday(time.Now())))
+
+		store, err := loadMetrics()
+		if err != nil {
+			fmt.Println("Error loading metrics:", err)
+			continue
+		}
+		_, err = s.ChannelMessageSend(os.Getenv("DIGEST_CHANNEL_ID"), formatDigest(store, time.Now().AddDate(0, 0, -7)))
+		if err != nil {
+			fmt.Println("Error sending weekly digest:", err)
+		}
+	}
+}
+
+func nextMonday(now time.Time) time.Time {
+	days := (8 - int(now.Weekday())) % 7
+	if days == 0 {
+		days = 7
+	}
+	year, month, day := now.AddDate(0, 0, days).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+func formatDigest(store MetricsStore, since time.Time) string {
+	var samples []MetricsSample
+	for _, sample := range store.Samples {
+		if !sample.Time.Before(since) {
+			samples = append(samples, sample)
+		}
+	}
+	if len(samples) == 0 {
+		return "WEEKLY DIGEST:\nNo metrics were collected this week."
+	}
+
+	online, tpsTotal := 0, 0.0
+	playtime := map[string]time.Duration{}
+	for _, sample := range samples {
+		if !sample.Online {
+			continue
+		}
+		online++
+		tpsTotal += sample.TPS
+		for _, player := range sample.Players {
+			playtime[player] += metricsInterval
+		}
+	}
+
+	var newPlayers []string
+	for player, firstSeen := range store.FirstSeen {
+		if !firstSeen.Before(since) {
+			newPlayers = append(newPlayers, player)
+		}
+	}
+	sort.Strings(newPlayers)
+
+	active := make([]string, 0, len(playtime))
+	for player := range playtime {
+		active = append(active, player)
+	}
+	sort.Slice(active, func(i, j int) bool { return playtime[active[i]] > playtime[active[j]] })
+	if len(active) > 5 {
+		active = active[:5]
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	avgTPS := 0.0
+	if online > 0 {
+		avgTPS = tpsTotal / float64(online)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("WEEKLY DIGEST:\n")
+	fmt.Fprintf(&sb, "New players: %d", len(newPlayers))
+	if len(newPlayers) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(newPlayers, ", "))
+	}
+	fmt.Fprintf(&sb, "\nDeaths: %d\n", last.Deaths-first.Deaths)
+	sb.WriteString("Most active:")
+	if len(active) == 0 {
+		sb.WriteString(" nobody")
+	}
+	for _, player := range active {
+		fmt.Fprintf(&sb, "\n  %s - %s", player, playtime[player].Round(time.Minute))
+	}
+	fmt.Fprintf(&sb, "\nUptime: %.1f%%\n", 100*float64(online)/float64(len(samples)))
+	fmt.Fprintf(&sb, "Average TPS: %.1f\n", avgTPS)
+	fmt.Fprintf(&sb, "Disk growth: %+.3f GB", float64(last.WorldSize-first.WorldSize)/1000000000)
+	return sb.String()
+}
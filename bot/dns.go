@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/dns"
+)
+
+func dnsHostname() string {
+	if host := os.Getenv("DNS_HOSTNAME"); host != "" {
+		return host
+	}
+	return "mc.xandaris.space"
+}
+
+func dnsResolver() string {
+	if resolver := os.Getenv("DNS_RESOLVER"); resolver != "" {
+		return resolver
+	}
+	return "1.1.1.1:53"
+}
+
+// dnsOptions names the server hostname's record, DNS_HOSTNAME as seen by DNS_RESOLVER.
+func dnsOptions() dns.Options {
+	return dns.Options{Hostname: dnsHostname(), Resolver: dnsResolver()}
+}
+
+func showDNSStatus(s *discordgo.Session, m *discordgo.MessageCreate) {
+	records, ip, drift, err := dns.Check(dnsOptions())
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "DNS check failed: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "DNS: %s\n", dnsHostname())
+	if len(records) == 0 {
+		sb.WriteString("No A records\n")
+	}
+	for _, record := range records {
+		fmt.Fprintf(&sb, "A %s (TTL %s)\n", record.IP, record.TTL)
+	}
+	fmt.Fprintf(&sb, "Public IP: %s\n", ip)
+	if drift != "" {
+		sb.WriteString("**DRIFT**: " + drift)
+	} else {
+		sb.WriteString("OK")
+	}
+	s.ChannelMessageSend(m.ChannelID, sb.String())
+}
+
+// watchDNS checks the DNS record every 10 minutes and alerts admins when it starts or stops drifting.
+func watchDNS(s *discordgo.Session) {
+	lastDrift := ""
+	ticker := time.NewTicker(10 * time.Minute)
+	for range ticker.C {
+		_, _, drift, err := dns.Check(dnsOptions())
+		if err != nil {
+			fmt.Println("Error checking DNS:", err)
+			continue
+		}
+		if drift != lastDrift {
+			if drift != "" {
+				sendAlert(s, adminChannel(), "**DNS DRIFT**: "+drift)
+			} else {
+				s.ChannelMessageSend(adminChannel(), fmt.Sprintf("DNS for %s matches the public IP again.", dnsHostname()))
+			}
+		}
+		lastDrift = drift
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hunterjsb/xn-mc/pkg/statuspage"
+)
+
+const maxEvents = 50
+
+// Event is a notable server occurrence parsed from the log.
+type Event struct {
+	Type    string    `json:"type"`
+	Player  string    `json:"player,omitempty"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+var (
+	events   []Event
+	eventsMu sync.Mutex
+
+	// [12:34:56] [Server thread/INFO]: Steve joined the game
+	serverLogRegex = regexp.MustCompile(`^\[[\d:]+\] \[Server thread/INFO\]: (.*)$`)
+	playerRegex    = regexp.MustCompile(`^(\w{3,16}) (.*)$`)
+)
+
+var deathPhrases = []string{
+	"was slain by", "was shot by", "was killed", "was blown up", "blew up", "was fireballed",
+	"was pummeled", "was impaled", "was skewered", "was squashed", "was squished", "was pricked",
+	"was poked to death", "was stung to death", "was obliterated", "was struck by lightning",
+	"was frozen", "froze to death", "drowned", "died", "fell", "hit the ground too hard",
+	"burned to death", "went up in flames", "walked into", "tried to swim in lava",
+	"discovered the floor was lava", "starved to death", "suffocated", "withered away",
+	"experienced kinetic energy", "went off with a bang", "didn't want to live",
+	"left the confines of this world", "was roasted", "was doomed to fall",
+}
+
+// recordLogEvent parses a server log line and records it if it is a join, death, start or stop.
+func recordLogEvent(line string) {
+	match := serverLogRegex.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+	message := match[1]
+
+	switch {
+	case strings.HasPrefix(message, "Done ("):
+		addEvent(Event{Type: "start", Message: "Server started"})
+		go setStatuspageComponent(statuspage.StatusOperational)
+		return
+	case message == "Stopping server":
+		addEvent(Event{Type: "stop", Message: "Server stopped"})
+		// Unexpected stops are reported as an outage by the watchdog
+		if stopExpected() {
+			go setStatuspageComponent(statuspage.StatusUnderMaintenance)
+		}
+		return
+	}
+
+	// Chat lines start with "<player>" so they never match here
+	player := playerRegex.FindStringSubmatch(message)
+	if player == nil {
+		return
+	}
+	if player[2] == "joined the game" {
+		addEvent(Event{Type: "join", Player: player[1], Message: message})
+		return
+	}
+	if isDeathMessage(player[2]) {
+		addEvent(Event{Type: "death", Player: player[1], Message: message})
+	}
+}
+
+// isDeathMessage reports whether text, the part of a log message after the player name, is a death.
+func isDeathMessage(text string) bool {
+	for _, phrase := range deathPhrases {
+		if strings.HasPrefix(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func addEvent(event Event) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	events = append(events, event)
+	if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+}
+
+// recentEvents returns the recorded events, newest first.
+func recentEvents() []Event {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	res := make([]Event, len(events))
+	for i, event := range events {
+		res[len(events)-1-i] = event
+	}
+	return res
+}
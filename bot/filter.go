@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Chat filter levels, set per channel with FILTER_LEVELS ("channelID:level,...").
+const (
+	filterOff    = "off"
+	filterSlurs  = "slurs"
+	filterStrict = "strict"
+)
+
+var (
+	filterWordRegex = regexp.MustCompile(`[\p{L}\p{N}@$]+`)
+	leetReplacer    = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+	slurWords, profanityWords map[string]bool
+	filterWordsOnce           sync.Once
+
+	// filterLevels are the FILTER_LEVELS overrides, parsed by loadConfig
+	filterLevels = map[string]string{}
+)
+
+func parseFilterLevels(raw string) map[string]string {
+	levels := parseKeyValues("FILTER_LEVELS", raw)
+	for channel, level := range levels {
+		if level != filterOff && level != filterSlurs && level != filterStrict {
+			fmt.Printf("Ignoring FILTER_LEVELS entry for %s: unknown level %q\n", channel, level)
+			delete(levels, channel)
+		}
+	}
+	return levels
+}
+
+// loadFilterWords reads FILTER_SLURS_FILE and FILTER_PROFANITY_FILE, one word per line.
+func loadFilterWords() {
+	slurWords = readWordList(os.Getenv("FILTER_SLURS_FILE"))
+	profanityWords = readWordList(os.Getenv("FILTER_PROFANITY_FILE"))
+}
+
+func readWordList(path string) map[string]bool {
+	words := map[string]bool{}
+	if path == "" {
+		return words
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Println("Error reading filter word list:", err)
+		return words
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.ToLower(strings.TrimSpace(scanner.Text())); word != "" && !strings.HasPrefix(word, "#") {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// filterLevel is the strictness for a channel. Slurs are always masked unless a channel is set to off.
+func filterLevel(channel string) string {
+	if level, ok := filterLevels[channel]; ok {
+		return level
+	}
+	return filterSlurs
+}
+
+// filterChat strips formatting codes from in-game text and masks listed words for the channel it is relayed to.
+func filterChat(channel string, text string) string {
+	text = stripFormatting(text)
+	level := filterLevel(channel)
+	if level == filterOff {
+		return text
+	}
+	filterWordsOnce.Do(loadFilterWords)
+
+	return filterWordRegex.ReplaceAllStringFunc(text, func(word string) string {
+		normalized := leetReplacer.Replace(strings.ToLower(word))
+		if slurWords[normalized] || (level == filterStrict && profanityWords[normalized]) {
+			runes := []rune(word)
+			return string(runes[0]) + strings.Repeat("\\*", len(runes)-1)
+		}
+		return word
+	})
+}
+
+// stripFormatting drops §x color and style codes.
+func stripFormatting(text string) string {
+	var sb strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '§' {
+			i++
+			continue
+		}
+		sb.WriteRune(runes[i])
+	}
+	return sb.String()
+}
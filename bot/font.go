@@ -0,0 +1,87 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyphs is a minimal 5x7 bitmap font, one row per space separated group.
+// Lowercase letters are drawn as uppercase.
+var glyphs = map[rune]string{
+	'A': ".###. #...# #...# ##### #...# #...# #...#",
+	'B': "####. #...# #...# ####. #...# #...# ####.",
+	'C': ".###. #...# #.... #.... #.... #...# .###.",
+	'D': "####. #...# #...# #...# #...# #...# ####.",
+	'E': "##### #.... #.... ####. #.... #.... #####",
+	'F': "##### #.... #.... ####. #.... #.... #....",
+	'G': ".###. #...# #.... #.### #...# #...# .####",
+	'H': "#...# #...# #...# ##### #...# #...# #...#",
+	'I': ".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'J': "..### ...#. ...#. ...#. ...#. #..#. .##..",
+	'K': "#...# #..#. #.#.. ##... #.#.. #..#. #...#",
+	'L': "#.... #.... #.... #.... #.... #.... #####",
+	'M': "#...# ##.## #.#.# #.#.# #...# #...# #...#",
+	'N': "#...# #...# ##..# #.#.# #..## #...# #...#",
+	'O': ".###. #...# #...# #...# #...# #...# .###.",
+	'P': "####. #...# #...# ####. #.... #.... #....",
+	'Q': ".###. #...# #...# #...# #.#.# #..#. .##.#",
+	'R': "####. #...# #...# ####. #.#.. #..#. #...#",
+	'S': ".#### #.... #.... .###. ....# ....# ####.",
+	'T': "##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'U': "#...# #...# #...# #...# #...# #...# .###.",
+	'V': "#...# #...# #...# #...# #...# .#.#. ..#..",
+	'W': "#...# #...# #...# #.#.# #.#.# #.#.# .#.#.",
+	'X': "#...# #...# .#.#. ..#.. .#.#. #...# #...#",
+	'Y': "#...# #...# .#.#. ..#.. ..#.. ..#.. ..#..",
+	'Z': "##### ....# ...#. ..#.. .#... #.... #####",
+	'0': ".###. #...# #..## #.#.# ##..# #...# .###.",
+	'1': "..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'2': ".###. #...# ....# ...#. ..#.. .#... #####",
+	'3': "####. ....# ....# .###. ....# ....# ####.",
+	'4': "...#. ..##. .#.#. #..#. ##### ...#. ...#.",
+	'5': "##### #.... ####. ....# ....# #...# .###.",
+	'6': ".###. #.... #.... ####. #...# #...# .###.",
+	'7': "##### ....# ...#. ..#.. .#... .#... .#...",
+	'8': ".###. #...# #...# .###. #...# #...# .###.",
+	'9': ".###. #...# #...# .#### ....# ....# .###.",
+	'.': "..... ..... ..... ..... ..... .##.. .##..",
+	':': "..... .##.. .##.. ..... .##.. .##.. .....",
+	'-': "..... ..... ..... .###. ..... ..... .....",
+	'/': "....# ....# ...#. ..#.. .#... #.... #....",
+	'%': "##..# ##..# ...#. ..#.. .#... #..## #..##",
+	'_': "..... ..... ..... ..... ..... ..... #####",
+	'(': "...#. ..#.. .#... .#... .#... ..#.. ...#.",
+	')': ".#... ..#.. ...#. ...#. ...#. ..#.. .#...",
+}
+
+// textWidth is the width in pixels of text drawn at the given scale.
+func textWidth(text string, scale int) int {
+	return len([]rune(text)) * (glyphWidth + 1) * scale
+}
+
+// drawText draws text with its top left corner at (x, y). Unknown characters are left blank.
+func drawText(img *image.RGBA, x, y int, text string, scale int, c color.Color) {
+	for _, r := range text {
+		rows := strings.Fields(glyphs[unicode.ToUpper(r)])
+		for row, pixels := range rows {
+			for col, pixel := range pixels {
+				if pixel != '#' {
+					continue
+				}
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.Set(x+col*scale+dx, y+row*scale+dy, c)
+					}
+				}
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var gameruleValueRegex = regexp.MustCompile(`is currently set to: (\S+)`)
+
+// gamerules are the known rules and whether they take a number rather than true/false.
+var gamerules = map[string]bool{
+	"announceAdvancements": false, "blockExplosionDropDecay": false, "commandBlockOutput": false,
+	"disableElytraMovementCheck": false, "disableRaids": false, "doDaylightCycle": false,
+	"doEntityDrops": false, "doFireTick": false, "doImmediateRespawn": false, "doInsomnia": false,
+	"doLimitedCrafting": false, "doMobLoot": false, "doMobSpawning": false, "doPatrolSpawning": false,
+	"doTileDrops": false, "doTraderSpawning": false, "doVinesSpread": false, "doWardenSpawning": false,
+	"doWeatherCycle": false, "drowningDamage": false, "enderPearlsVanishOnDeath": false,
+	"fallDamage": false, "fireDamage": false, "forgiveDeadPlayers": false, "freezeDamage": false,
+	"globalSoundEvents": false, "keepInventory": false, "lavaSourceConversion": false,
+	"logAdminCommands": false, "mobExplosionDropDecay": false, "mobGriefing": false,
+	"naturalRegeneration": false, "reducedDebugInfo": false, "sendCommandFeedback": false,
+	"showDeathMessages": false, "spectatorsGenerateChunks": false, "tntExplosionDropDecay": false,
+	"universalAnger": false, "waterSourceConversion": false,
+
+	"commandModificationBlockLimit": true, "maxCommandChainLength": true, "maxEntityCramming": true,
+	"playersSleepingPercentage": true, "randomTickSpeed": true, "snowAccumulationHeight": true,
+	"spawnRadius": true,
+}
+
+// resolveGamerule matches name to a known rule regardless of case. Otherwise it returns the
+// known rules containing name, for typos and partial names.
+func resolveGamerule(name string) (rule string, suggestions []string) {
+	for known := range gamerules {
+		if strings.EqualFold(known, name) {
+			return known, nil
+		}
+		if strings.Contains(strings.ToLower(known), strings.ToLower(name)) {
+			suggestions = append(suggestions, known)
+		}
+	}
+	sort.Strings(suggestions)
+	return "", suggestions
+}
+
+// setGamerule validates value for rule and applies it, returning the server's response.
+func setGamerule(rule string, value string) (string, error) {
+	if numeric, known := gamerules[rule]; known {
+		if _, err := strconv.Atoi(value); numeric && err != nil {
+			return "", fmt.Errorf("%s takes a number", rule)
+		}
+		if value != "true" && value != "false" && !numeric {
+			return "", fmt.Errorf("%s takes true or false", rule)
+		}
+	}
+	return rconExecute(fmt.Sprintf("gamerule %s %s", rule, value))
+}
+
+func gameruleValue(rule string) (string, error) {
+	response, err := rconExecute("gamerule " + rule)
+	if err != nil {
+		return "", err
+	}
+	match := gameruleValueRegex.FindStringSubmatch(response)
+	if match == nil {
+		return "", fmt.Errorf("unexpected gamerule response: %q", response)
+	}
+	return match[1], nil
+}
+
+// handleGamerule shows or sets a gamerule: `gamerule [set] <rule> [value]`. Partial or
+// misspelled names get suggestions, and names this list doesn't know are left to the server.
+func handleGamerule(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) > 0 && args[0] == "set" {
+		args = args[1:]
+	}
+	if len(args) == 0 || len(args) > 2 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `gamerule [set] <rule> [value]`")
+		return
+	}
+
+	rule, suggestions := resolveGamerule(args[0])
+	if rule == "" {
+		if len(suggestions) > 0 {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Unknown gamerule `%s`. Did you mean: %s", args[0], strings.Join(suggestions, ", ")))
+			return
+		}
+		rule = args[0]
+	}
+
+	var response string
+	var err error
+	if len(args) == 1 {
+		response, err = rconExecute("gamerule " + rule)
+	} else {
+		response, err = setGamerule(rule, args[1])
+	}
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "**ERROR**: "+err.Error())
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, response)
+}
+
+// handleQOL covers routine tweaks without gamerule syntax:
+//
+//	qol                   current sleep percentage, keepInventory and fire tick
+//	qol sleep <percent>   players needed in bed to skip the night
+//	qol keepinv [fix]     check keepInventory is off, turning it off with fix
+//	qol firetick <on|off> whether fire spreads and burns blocks
+func handleQOL(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		var sb strings.Builder
+		sb.WriteString("QOL:\n")
+		for _, rule := range []string{"playersSleepingPercentage", "keepInventory", "doFireTick"} {
+			value, err := gameruleValue(rule)
+			if err != nil {
+				value = "unknown (" + err.Error() + ")"
+			}
+			fmt.Fprintf(&sb, "%s: %s\n", rule, value)
+		}
+		s.ChannelMessageSend(m.ChannelID, sb.String())
+		return
+	}
+
+	var response string
+	var err error
+	switch {
+	case args[0] == "sleep" && len(args) == 2:
+		percent, parseErr := strconv.Atoi(strings.TrimSuffix(args[1], "%"))
+		if parseErr != nil || percent < 0 || percent > 100 {
+			s.ChannelMessageSend(m.ChannelID, "Sleep percentage must be between 0 and 100.")
+			return
+		}
+		response, err = setGamerule("playersSleepingPercentage", strconv.Itoa(percent))
+	case args[0] == "keepinv" && len(args) == 1:
+		var value string
+		if value, err = gameruleValue("keepInventory"); err == nil {
+			response = "keepInventory is off."
+			if value != "false" {
+				response = "**keepInventory is ON.** Run `qol keepinv fix` to turn it off."
+			}
+		}
+	case args[0] == "keepinv" && len(args) == 2 && args[1] == "fix":
+		response, err = setGamerule("keepInventory", "false")
+	case args[0] == "firetick" && len(args) == 2 && slices.Contains([]string{"on", "off"}, args[1]):
+		response, err = setGamerule("doFireTick", strconv.FormatBool(args[1] == "on"))
+	default:
+		s.ChannelMessageSend(m.ChannelID, "Usage: `qol [sleep <percent> | keepinv [fix] | firetick <on|off>]`")
+		return
+	}
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "**ERROR**: "+err.Error())
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, response)
+}
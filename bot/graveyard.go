@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// relayToSpectators whispers a #graveyard message to every spectator in game, so dead
+// players can talk without touching live chat.
+func relayToSpectators(m *discordgo.MessageCreate) {
+	name := m.Author.Username
+	if m.Member != nil && m.Member.Nick != "" {
+		name = m.Member.Nick
+	}
+
+	text, err := json.Marshal([]map[string]string{
+		{"text": "[Graveyard] ", "color": "dark_gray"},
+		{"text": name + ": " + m.Content, "color": "gray"},
+	})
+	if err != nil {
+		fmt.Println("Error encoding graveyard message:", err)
+		return
+	}
+	if _, err = rconExecute("tellraw @a[gamemode=spectator] " + string(text)); err != nil {
+		fmt.Println("Error relaying graveyard message:", err)
+	}
+}
+
+// relayGraveyardChat posts in-game chat from spectators to GRAVEYARD_CHANNEL_ID.
+func relayGraveyardChat(s *discordgo.Session, line string) {
+	graveyardChannelID := os.Getenv("GRAVEYARD_CHANNEL_ID")
+	if graveyardChannelID == "" {
+		return
+	}
+	player, message, ok := parseChatLine(line)
+	if !ok {
+		return
+	}
+
+	// "Test passed, count: 1" when the player is a spectator
+	response, err := rconExecute(fmt.Sprintf("execute if entity @a[name=%s,gamemode=spectator]", player))
+	if err != nil || !strings.HasPrefix(response, "Test passed") {
+		return
+	}
+	s.ChannelMessageSend(graveyardChannelID, fmt.Sprintf("**%s**: %s", player, filterChat(graveyardChannelID, message)))
+}
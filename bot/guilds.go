@@ -0,0 +1,21 @@
+package main
+
+import "github.com/bwmarrin/discordgo"
+
+// guildChannels maps a guild ID to the channel that accepts commands in that guild, from
+// GUILD_CHANNELS ("guildID:channelID,...").
+var guildChannels map[string]string
+
+// isCommandChannel reports whether m was sent in the main channel or its guild's command channel.
+func isCommandChannel(m *discordgo.MessageCreate) bool {
+	return m.ChannelID == channelID || (m.GuildID != "" && guildChannels[m.GuildID] == m.ChannelID)
+}
+
+// replyChannel is where a command's responses go: the channel it came from, or the main
+// channel for actions the bot triggers on its own.
+func replyChannel(m *discordgo.MessageCreate) string {
+	if m == nil {
+		return channelID
+	}
+	return m.ChannelID
+}
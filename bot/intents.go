@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// gatewayIntents declares exactly the intents the enabled features need.
+func gatewayIntents() discordgo.Intent {
+	// Prefix commands, the graveyard relay and DM whispers read message content, which is privileged
+	var intents discordgo.Intent
+	if readsMessageContent() {
+		intents |= discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages | discordgo.IntentsMessageContent
+	}
+
+	// Rules acknowledgment and the starboard react to reactions
+	if os.Getenv("RULES_MESSAGE_ID") != "" || os.Getenv("STARBOARD_CHANNEL_ID") != "" {
+		intents |= discordgo.IntentsGuildMessageReactions
+	}
+	return intents
+}
+
+// readsMessageContent reports whether the message handlers are enabled. With MESSAGE_CONTENT=false
+// the bot runs without the privileged intent and only does its background work: log relay, alerts,
+// pinned messages, reactions and the HTTP API.
+func readsMessageContent() bool {
+	return os.Getenv("MESSAGE_CONTENT") != "false"
+}
+
+// configureSharding applies SHARD_ID and SHARD_COUNT, leaving a single unsharded session by default.
+func configureSharding(s *discordgo.Session) error {
+	if os.Getenv("SHARD_COUNT") == "" {
+		return nil
+	}
+	count, err := strconv.Atoi(os.Getenv("SHARD_COUNT"))
+	if err != nil || count < 1 {
+		return fmt.Errorf("invalid SHARD_COUNT %q", os.Getenv("SHARD_COUNT"))
+	}
+	id, err := strconv.Atoi(os.Getenv("SHARD_ID"))
+	if err != nil || id < 0 || id >= count {
+		return fmt.Errorf("invalid SHARD_ID %q for %d shards", os.Getenv("SHARD_ID"), count)
+	}
+	s.ShardID = id
+	s.ShardCount = count
+	return nil
+}
+
+// explainOpenError turns gateway close codes for intent problems into an actionable message.
+func explainOpenError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "4014"):
+		return fmt.Errorf("Discord refused a privileged intent, enable Message Content Intent for the bot in the Developer Portal: %w", err)
+	case strings.Contains(err.Error(), "4013"):
+		return fmt.Errorf("Discord rejected the requested intents %d: %w", gatewayIntents(), err)
+	}
+	return err
+}
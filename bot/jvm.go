@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func handleJVM(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	usage := "Usage: `jvm stats`, `jvm heapdump confirm`"
+	if len(args) == 0 {
+		s.ChannelMessageSend(m.ChannelID, usage)
+		return
+	}
+
+	pid, err := serverPID()
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, err.Error())
+		return
+	}
+
+	switch args[0] {
+	case "stats":
+		stats, err := jvmStats(pid)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, "Failed to read JVM stats: "+err.Error())
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, stats)
+	case "heapdump":
+		if !isAdmin(s, m) {
+			s.ChannelMessageSend(m.ChannelID, "Only admins can take heap dumps.")
+			return
+		}
+		if len(args) < 2 || args[1] != "confirm" {
+			s.ChannelMessageSend(m.ChannelID, "A heap dump pauses the server and can use several GB of disk. Run `jvm heapdump confirm` to continue.")
+			return
+		}
+		ctx, done, err := startTask("heapdump")
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, err.Error())
+			return
+		}
+		defer done()
+
+		s.ChannelMessageSend(m.ChannelID, "Writing heap dump... Use `cancel heapdump` to abort.")
+		result := heapDump(ctx, pid)
+		if ctx.Err() != nil {
+			result = taskError(ctx, "heapdump")
+		}
+		s.ChannelMessageSend(m.ChannelID, result)
+	default:
+		s.ChannelMessageSend(m.ChannelID, usage)
+	}
+}
+
+// jvmStats reports heap usage and GC totals from jstat, and the thread count from /proc.
+func jvmStats(pid int) (string, error) {
+	out, err := exec.Command("jstat", "-gc", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 2 {
+		return "", fmt.Errorf("unexpected jstat output: %q", out)
+	}
+
+	// Map each jstat column header to its value, sizes are in KB and times in seconds
+	gc := map[string]float64{}
+	values := strings.Fields(lines[1])
+	for i, header := range strings.Fields(lines[0]) {
+		if i < len(values) {
+			gc[header], _ = strconv.ParseFloat(values[i], 64)
+		}
+	}
+	used := gc["S0U"] + gc["S1U"] + gc["EU"] + gc["OU"]
+	capacity := gc["S0C"] + gc["S1C"] + gc["EC"] + gc["OC"]
+
+	threads, err := processThreads(pid)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("JVM:\nHeap: %.3f / %.3f GB\nYoung GC: %.0f (%.3fs)\nFull GC: %.0f (%.3fs)\nTotal GC time: %.3fs\nThreads: %d",
+		used/1000000, capacity/1000000, gc["YGC"], gc["YGCT"], gc["FGC"], gc["FGCT"], gc["GCT"], threads), nil
+}
+
+func processThreads(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, found := strings.CutPrefix(line, "Threads:"); found {
+			return strconv.Atoi(strings.TrimSpace(value))
+		}
+	}
+	return 0, fmt.Errorf("Threads not found for pid %d", pid)
+}
+
+// heapDump writes a heap dump to ../server/heapdumps and describes the result.
+func heapDump(ctx context.Context, pid int) string {
+	dir, err := filepath.Abs("../server/heapdumps")
+	if err != nil {
+		return "Failed to resolve heap dump directory: " + err.Error()
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return "Failed to create heap dump directory: " + err.Error()
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("heap-%s.hprof", time.Now().Format("20060102-150405")))
+	out, err := exec.CommandContext(ctx, "jcmd", strconv.Itoa(pid), "GC.heap_dump", path).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("Failed to write heap dump: %s\n%s", err, out)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "Heap dump did not produce a file: " + err.Error()
+	}
+	return fmt.Sprintf("Heap dump written to %s (%.3f GB)", path, float64(info.Size())/1000000000)
+}
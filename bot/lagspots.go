@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const lagspotCount = 10
+
+// ChunkEntities counts the entities saved in one chunk.
+type ChunkEntities struct {
+	X, Z   int32
+	Total  int
+	ByType map[string]int
+}
+
+// handleLagspots reports the chunks with the most saved entities, as of a fresh save-all.
+func handleLagspots(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	dimension, dir := "overworld", ""
+	if len(args) > 0 {
+		dimension = args[0]
+	}
+	if dimension != "overworld" {
+		var ok bool
+		if dir, ok = dimensionDirs[dimension]; !ok {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `lagspots [overworld|nether|end]`")
+			return
+		}
+	}
+
+	// Flush loaded entities to disk so the counts are current
+	if _, err := rconExecute("save-all flush"); err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Could not save the world first, counts may be stale: "+err.Error())
+	}
+
+	ctx, done, err := startTask("lagspots")
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, err.Error())
+		return
+	}
+	defer done()
+
+	chunks, err := countChunkEntities(ctx, filepath.Join(worldPath(), dir, "entities"))
+	if ctx.Err() != nil {
+		s.ChannelMessageSend(m.ChannelID, taskError(ctx, "lagspots"))
+		return
+	}
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read entity data: "+err.Error())
+		return
+	}
+	if len(chunks) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No entities saved in the "+dimension+".")
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "LAGSPOTS (%s):\n", dimension)
+	for i, chunk := range chunks {
+		if i == lagspotCount {
+			break
+		}
+		topType, topCount := "", 0
+		for typ, count := range chunk.ByType {
+			if count > topCount {
+				topType, topCount = typ, count
+			}
+		}
+		fmt.Fprintf(&sb, "%d. chunk %d, %d (x %d, z %d): %d entities, mostly %s (%d)\n",
+			i+1, chunk.X, chunk.Z, chunk.X*16+8, chunk.Z*16+8, chunk.Total, strings.TrimPrefix(topType, "minecraft:"), topCount)
+	}
+	s.ChannelMessageSend(m.ChannelID, sb.String())
+}
+
+// countChunkEntities reads every entities region file in dir, busiest chunks first.
+func countChunkEntities(ctx context.Context, dir string) ([]ChunkEntities, error) {
+	regions, err := filepath.Glob(filepath.Join(dir, "*.mca"))
+	if err != nil {
+		return nil, err
+	}
+
+	var res []ChunkEntities
+	for _, region := range regions {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		chunks, err := readRegionChunks(region)
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			entities, _ := chunk["Entities"].([]any)
+			position, _ := chunk["Position"].([]int32)
+			if len(entities) == 0 || len(position) != 2 {
+				continue
+			}
+
+			counted := ChunkEntities{X: position[0], Z: position[1], Total: len(entities), ByType: map[string]int{}}
+			for _, entity := range entities {
+				if compound, ok := entity.(map[string]any); ok {
+					id, _ := compound["id"].(string)
+					counted.ByType[id]++
+				}
+			}
+			res = append(res, counted)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Total > res[j].Total })
+	return res, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const leaderboardHeader = "LEADERBOARD"
+
+var scoreRegex = regexp.MustCompile(`^(\S+) has (-?\d+) `)
+
+type Score struct {
+	Player string
+	Value  int
+}
+
+// syncLeaderboard keeps a single pinned message in the leaderboard channel up to date
+// with the scores of LEADERBOARD_OBJECTIVE.
+func syncLeaderboard(s *discordgo.Session) {
+	objective := os.Getenv("LEADERBOARD_OBJECTIVE")
+	leaderboardChannelID := os.Getenv("LEADERBOARD_CHANNEL_ID")
+	if leaderboardChannelID == "" {
+		leaderboardChannelID = channelID
+	}
+
+	interval := 5 * time.Minute
+	if raw := os.Getenv("LEADERBOARD_INTERVAL"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			fmt.Println("Invalid LEADERBOARD_INTERVAL, using default:", err)
+		} else {
+			interval = parsed
+		}
+	}
+
+	messageID := findPinnedMessage(s, leaderboardChannelID, leaderboardHeader)
+	ticker := time.NewTicker(interval)
+	for ; true; <-ticker.C {
+		scores, err := readScoreboard(objective)
+		if err != nil {
+			fmt.Println("Error reading scoreboard:", err)
+			continue
+		}
+		content := formatLeaderboard(objective, scores)
+
+		if messageID != "" {
+			_, err = s.ChannelMessageEdit(leaderboardChannelID, messageID, content)
+			if err == nil {
+				continue
+			}
+			// The message was probably deleted, post a new one
+			fmt.Println("Error editing leaderboard message:", err)
+		}
+
+		msg, err := s.ChannelMessageSend(leaderboardChannelID, content)
+		if err != nil {
+			fmt.Println("Error sending leaderboard message:", err)
+			continue
+		}
+		messageID = msg.ID
+		if err = s.ChannelMessagePin(leaderboardChannelID, messageID); err != nil {
+			fmt.Println("Error pinning leaderboard message:", err)
+		}
+	}
+}
+
+// findPinnedMessage looks for a message starting with header that the bot pinned on a previous run.
+func findPinnedMessage(s *discordgo.Session, channelID string, header string) string {
+	pinned, err := s.ChannelMessagesPinned(channelID)
+	if err != nil {
+		fmt.Println("Error fetching pinned messages:", err)
+		return ""
+	}
+	for _, msg := range pinned {
+		if msg.Author != nil && msg.Author.ID == s.State.User.ID && strings.HasPrefix(msg.Content, header) {
+			return msg.ID
+		}
+	}
+	return ""
+}
+
+func readScoreboard(objective string) ([]Score, error) {
+	response, err := rconExecute("scoreboard players list")
+	if err != nil {
+		return nil, err
+	}
+
+	// "There are 2 tracked entity/entities: Alice, Bob"
+	_, names, found := strings.Cut(response, ": ")
+	if !found {
+		return nil, nil
+	}
+
+	var scores []Score
+	for _, name := range strings.Split(names, ", ") {
+		response, err = rconExecute(fmt.Sprintf("scoreboard players get %s %s", name, objective))
+		if err != nil {
+			return nil, err
+		}
+
+		// "Alice has 12 [objective]", anything else means no score for this objective
+		match := scoreRegex.FindStringSubmatch(response)
+		if match == nil {
+			continue
+		}
+		value, _ := strconv.Atoi(match[2])
+		scores = append(scores, Score{Player: match[1], Value: value})
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Value > scores[j].Value })
+	return scores, nil
+}
+
+func formatLeaderboard(objective string, scores []Score) string {
+	size := 10
+	if raw := os.Getenv("LEADERBOARD_SIZE"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			size = parsed
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s\n", leaderboardHeader, objective)
+	if len(scores) == 0 {
+		sb.WriteString("No scores yet.\n")
+	}
+	for i, score := range scores {
+		if i == size {
+			break
+		}
+		fmt.Fprintf(&sb, "%d. %s - %d\n", i+1, score.Player, score.Value)
+	}
+	sb.WriteString("Updated " + timestamp(time.Now()))
+	return sb.String()
+}
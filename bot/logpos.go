@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+const defaultLogCatchupBytes = 64 * 1024
+
+// LogPosition is how far the relay got in the log file, identified by inode.
+type LogPosition struct {
+	Inode  uint64
+	Offset int64
+}
+
+// logInode identifies the log file the saved position refers to.
+var logInode uint64
+
+func logPositionFile() string {
+	if path := os.Getenv("LOG_POSITION_FILE"); path != "" {
+		return path
+	}
+	return "logpos.json"
+}
+
+func fileInode(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Ino
+	}
+	return 0
+}
+
+// restoreLogPosition picks up where the relay left off before the bot restarted. Lines
+// written while the bot was down are relayed, up to LOG_CATCHUP_BYTES; anything older is
+// skipped and its size returned.
+func restoreLogPosition(logFilePath string) (offset int64, skipped int64) {
+	info, err := os.Stat(logFilePath)
+	if err != nil {
+		return 0, 0
+	}
+	logInode = fileInode(info)
+
+	var stored LogPosition
+	if err = readJSONFile(logPositionFile(), &stored); err != nil {
+		fmt.Println("Error reading log position:", err)
+	}
+
+	// A different or truncated file means the server restarted while we were down
+	if stored.Inode == logInode && stored.Offset <= info.Size() {
+		offset = stored.Offset
+	}
+
+	catchup := int64(defaultLogCatchupBytes)
+	if raw := os.Getenv("LOG_CATCHUP_BYTES"); raw != "" {
+		if parsed, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			catchup = parsed
+		}
+	}
+	if skipped = info.Size() - offset - catchup; skipped > 0 {
+		// Start at the next full line rather than in the middle of one
+		partial, err := lineRemainder(logFilePath, offset+skipped)
+		if err != nil {
+			fmt.Println("Error reading log file:", err)
+		}
+		skipped += partial
+		return offset + skipped, skipped
+	}
+	return offset, 0
+}
+
+// lineRemainder returns how many bytes of the line containing offset are left, up to and
+// including its newline, or 0 if a line starts at offset.
+func lineRemainder(path string, offset int64) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil || offset == 0 {
+		return 0, err
+	}
+	defer file.Close()
+
+	// Read from the byte before offset to see whether it ends a line
+	if _, err = file.Seek(offset-1, io.SeekStart); err != nil {
+		return 0, err
+	}
+	rest, err := bufio.NewReader(file).ReadBytes('\n')
+	if err == io.EOF {
+		err = nil
+	}
+	return max(int64(len(rest))-1, 0), err
+}
+
+// checkLogRotation resets the read position when the log file was replaced or truncated,
+// as happens when the server is started again.
+func checkLogRotation(file *os.File, offset int64) int64 {
+	info, err := file.Stat()
+	if err != nil {
+		return offset
+	}
+	if inode := fileInode(info); inode != logInode || info.Size() < offset {
+		logInode = inode
+		return 0
+	}
+	return offset
+}
+
+func saveLogPosition(offset int64) {
+	if err := writeJSONFile(logPositionFile(), LogPosition{Inode: logInode, Offset: offset}, 0); err != nil {
+		fmt.Println("Error saving log position:", err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	logWatchQuiet    = 5 * time.Second
+	logWatchMaxLines = 40
+)
+
+// delayedOutput lists console commands whose results are written to the log after the rcon
+// response, with the pattern their lines match and how long to wait for them.
+var delayedOutput = []struct {
+	Command string
+	Pattern *regexp.Regexp
+	Timeout time.Duration
+}{
+	{"spark", regexp.MustCompile(`\[⚡\]`), 30 * time.Second},
+	{"chunky", regexp.MustCompile(`\[Chunky\]`), 15 * time.Second},
+	{"bluemap", regexp.MustCompile(`\[BlueMap\]`), 15 * time.Second},
+}
+
+var logPrefixRegex = regexp.MustCompile(`^\[[\d:]+(?: \w+)?\]:? (?:\[[^\]]+\]: )?`)
+
+// logWatcher receives the log lines matching its pattern while it is registered.
+type logWatcher struct {
+	pattern *regexp.Regexp
+	timeout time.Duration
+	lines   chan string
+}
+
+var (
+	logWatchers   = map[*logWatcher]bool{}
+	logWatchersMu sync.Mutex
+)
+
+// feedLogWatchers hands a server log line to any watcher it matches.
+func feedLogWatchers(line string) {
+	logWatchersMu.Lock()
+	defer logWatchersMu.Unlock()
+	for watcher := range logWatchers {
+		if watcher.pattern.MatchString(line) {
+			select {
+			case watcher.lines <- logPrefixRegex.ReplaceAllString(line, ""):
+			default: // drop lines past what the watcher can hold
+			}
+		}
+	}
+}
+
+// watchLog registers a watcher for the log lines matching pattern. Start it before running
+// the command, then collect the lines.
+func watchLog(pattern *regexp.Regexp, timeout time.Duration) *logWatcher {
+	watcher := &logWatcher{pattern: pattern, timeout: timeout, lines: make(chan string, logWatchMaxLines)}
+	logWatchersMu.Lock()
+	logWatchers[watcher] = true
+	logWatchersMu.Unlock()
+	return watcher
+}
+
+func (w *logWatcher) stop() {
+	logWatchersMu.Lock()
+	delete(logWatchers, w)
+	logWatchersMu.Unlock()
+}
+
+// collect waits for matching lines until the timeout, or until the log has been quiet for a
+// few seconds after the first match, and unregisters the watcher.
+func (w *logWatcher) collect() []string {
+	defer w.stop()
+
+	var captured []string
+	deadline := time.After(w.timeout)
+	var quiet <-chan time.Time
+	for {
+		select {
+		case line := <-w.lines:
+			captured = append(captured, line)
+			if len(captured) == logWatchMaxLines {
+				return captured
+			}
+			quiet = time.After(logWatchQuiet)
+		case <-quiet:
+			return captured
+		case <-deadline:
+			return captured
+		}
+	}
+}
+
+// delayedOutputWatch starts a watcher for cmd if it is known to log its results, or returns nil.
+func delayedOutputWatch(cmd string) *logWatcher {
+	name := strings.ToLower(strings.TrimPrefix(strings.Fields(cmd + " ")[0], "/"))
+	for _, delayed := range delayedOutput {
+		if name == delayed.Command {
+			return watchLog(delayed.Pattern, delayed.Timeout)
+		}
+	}
+	return nil
+}
@@ -1,19 +1,21 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
-	"io"
 	"os"
-	"os/exec"
 	"os/signal"
-	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
-	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/gorcon/rcon"
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/relay"
 	"github.com/joho/godotenv"
 )
 
@@ -22,211 +24,345 @@ var (
 	channelID     string
 	commandPrefix byte
 	rconClient    *rcon.Conn
+	rconMu        sync.Mutex
 )
 
-func init() {
+// loadConfig loads ../.env into the environment and reads the globals from it.
+// Variables already set in the environment take precedence over the file.
+func loadConfig() {
 	err := godotenv.Load("../.env") // Adjust the path as necessary
 	if err != nil {
-		fmt.Println("Error loading .env file")
-		return
+		fmt.Println("Error loading .env file:", err)
 	}
 
 	// Get environment variables
 	channelID = os.Getenv("DISCORD_CHANNEL_ID")
-	commandPrefix = os.Getenv("COMMAND_PREFIX")[0]
+	guildChannels = parseKeyValues("GUILD_CHANNELS", os.Getenv("GUILD_CHANNELS"))
+	if prefix := os.Getenv("COMMAND_PREFIX"); prefix != "" {
+		commandPrefix = prefix[0]
+	}
+	if controller, err = newServerController(); err != nil {
+		fmt.Println("Error configuring server backend:", err)
+		os.Exit(1)
+	}
+	if err = loadTheme(); err != nil {
+		fmt.Println("Error loading theme, using defaults:", err)
+	}
+	commandTimeouts = parseCommandTimeouts(os.Getenv("COMMAND_TIMEOUTS"))
+	filterLevels = parseFilterLevels(os.Getenv("FILTER_LEVELS"))
+	starboardThresholds = parseStarboardThresholds(os.Getenv("STARBOARD_CHANNELS"))
+}
+
+// parseKeyValues parses "key:value,key:value" read from the environment variable name,
+// which is named in the warning for malformed entries.
+func parseKeyValues(name string, raw string) map[string]string {
+	res := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, ":")
+		if !found {
+			fmt.Printf("Ignoring malformed %s entry: %s\n", name, pair)
+			continue
+		}
+		res[key] = value
+	}
+	return res
 }
 
 func main() {
-	// Create a new Discord session using the provided bot token.
-	dg, err := discordgo.New("Bot " + os.Getenv("DISCORD_TOKEN"))
+	probe := flag.Bool("probe", false, "shorthand for the probe subcommand")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [run|probe|selftest|migrate|export-config]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	loadConfig()
+
+	command := flag.Arg(0)
+	if *probe {
+		command = "probe"
+	}
+	switch command {
+	case "", "run":
+		runBot()
+	case "probe":
+		runProbe()
+	case "selftest":
+		if !runSelftest() {
+			os.Exit(1)
+		}
+	case "migrate":
+		if err := migrateStores(); err != nil {
+			fmt.Println("Error migrating stores:", err)
+			os.Exit(1)
+		}
+	case "export-config":
+		exportConfig(os.Stdout)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// runBot connects to Discord and runs until interrupted.
+func runBot() {
+	// Create a new Discord session using the provided bot token, asking only for the
+	// events the enabled features use.
+	bot, err := mcbot.New(mcbot.Options{Token: os.Getenv("DISCORD_TOKEN"), Intents: gatewayIntents()})
 	if err != nil {
 		fmt.Println("error creating Discord session,", err)
 		return
 	}
+	dg := bot.Session
 
 	// Register the messageCreate func as a callback for MessageCreate events.
-	dg.AddHandler(messageCreate)
+	if readsMessageContent() {
+		commandRouter = newCommandRouter()
+		dg.AddHandler(messageCreate)
+	}
 
-	// We only care about receiving message events.
-	dg.Identify.Intents = discordgo.IntentsGuildMessages
+	// Report gateway disconnects once the session comes back
+	dg.AddHandler(onDisconnect)
+	dg.AddHandler(onResumed)
+	dg.AddHandler(onReady)
 
-	// Open a websocket connection to Discord and begin listening.
-	err = dg.Open()
-	if err != nil {
-		fmt.Println("error opening connection,", err)
+	// Gate the player role behind a reaction on the rules message, if configured
+	if os.Getenv("RULES_MESSAGE_ID") != "" {
+		dg.AddHandler(rulesReactionAdd)
+		bot.Go(setupRules)
+	}
+
+	// Repost popular messages to the starboard, if configured
+	if os.Getenv("STARBOARD_CHANNEL_ID") != "" {
+		dg.AddHandler(starboardReactionAdd)
+	}
+
+	if err = configureSharding(dg); err != nil {
+		fmt.Println("Error configuring sharding:", err)
 		return
 	}
 
+	// Report missing permissions before features fail on them
+	bot.Go(auditPermissions)
+
 	// Start streaming server logs
-	go streamServerLogsToDiscord(dg, channelID, "../server/server.out")
+	bot.Go(func(s *discordgo.Session) { streamServerLogsToDiscord(s, channelID) })
 
-	// Wait here until CTRL-C or other term signal is received.
-	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
-	sc := make(chan os.Signal, 1)
-	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-sc
+	// Keep the scoreboard leaderboard in sync, if configured
+	if os.Getenv("LEADERBOARD_OBJECTIVE") != "" {
+		bot.Go(syncLeaderboard)
+	}
 
-	// Cleanly close down the Discord session.
-	dg.Close()
-}
+	// Keep the pinned server info message up to date, if configured
+	if os.Getenv("SERVER_INFO_CHANNEL_ID") != "" {
+		bot.Go(syncServerInfo)
+	}
 
-// This function will be called (due to AddHandler above) every time a new
-// message is created on any channel that the authenticated bot has access to.
-func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// If the message is "ping" reply with "Pong!"
-	if m.Content == "ping" {
-		s.ChannelMessageSend(m.ChannelID, "Pong! github: https://github.com/hunterjsb/xn-mc?tab=readme-ov-file#xn-mc")
+	// Post rendered status cards on a schedule, if configured
+	if os.Getenv("STATUS_CHANNEL_ID") != "" && os.Getenv("STATUS_CARD_INTERVAL") != "" {
+		bot.Go(postStatusCards)
 	}
 
-	// Ignore all messages created by the bot itself OR in other channels OR no command prefix
-	if m.Author.ID == s.State.User.ID || m.ChannelID != channelID || m.Content[0] != commandPrefix {
-		return
+	// Sample server metrics and post the weekly digest, if configured
+	go collectMetrics()
+	if os.Getenv("DIGEST_CHANNEL_ID") != "" {
+		bot.Go(postWeeklyDigests)
 	}
-	command := m.Content[1:]
 
-	// Use a switch statement to handle different commands
-	switch command {
-	case "status":
-		checkMinecraftServerStatus(s, m)
-	case "start":
-		startMinecraftServer(s, m)
-	case "stop":
-		stopMinecraftServer(s, m)
-		if rconClient != nil {
-			rconClient.Close()
-		}
-	case "mem":
-		s.ChannelMessageSend(m.ChannelID, ReadMemoryStats().ToStr())
-	default:
-		// Relay any other command to the server
-		if rconClient == nil {
-			rconClient = connectRcon(s)
+	// Restart the server automatically when it degrades, if configured
+	if os.Getenv("AUTO_RESTART_MIN_TPS") != "" || os.Getenv("AUTO_RESTART_MAX_RSS_MB") != "" {
+		policy, err := loadAutoRestartPolicy()
+		if err != nil {
+			fmt.Println("Error loading auto restart policy:", err)
+		} else {
+			bot.Go(func(s *discordgo.Session) { watchServerHealth(s, policy) })
 		}
-		executeRcon(s, command)
 	}
-}
 
-func connectRcon(s *discordgo.Session) *rcon.Conn {
-	conn, err := rcon.Dial(os.Getenv("RCON_IP"), os.Getenv("RCON_PW"))
-	if err != nil {
-		errStr := fmt.Sprintf("**ERROR**: Could not connect to minecraft rcon on %s: %s", os.Getenv("RCON_IP"), err.Error())
-		s.ChannelMessageSend(channelID, errStr)
+	// Deliver critical alerts through a webhook while the gateway is down, if configured
+	if os.Getenv("ALERT_WEBHOOK_URL") != "" {
+		go deliverQueuedAlerts()
 	}
-	return conn
-}
 
-func executeRcon(s *discordgo.Session, cmd string) {
-	response, err := rconClient.Execute(cmd)
-	if err != nil {
-		s.ChannelMessageSend(channelID, "ERROR: "+err.Error())
+	// Watch for the server going down unexpectedly
+	bot.Go(watchForOutages)
+
+	// Alert admins when the server hostname stops pointing at us, if enabled
+	if os.Getenv("DNS_CHECK") == "true" {
+		bot.Go(watchDNS)
 	}
-	s.ChannelMessageSend(channelID, response)
-}
 
-func checkMinecraftServerStatus(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// Command to check if a process with 'server.jar' is running
-	cmd := exec.Command("pgrep", "-f", "server.jar")
-	err := cmd.Run()
+	// Keep the server hostname pointed at our public IP, if configured
+	if os.Getenv("DDNS_PROVIDER") != "" {
+		provider, err := newDNSProvider()
+		if err != nil {
+			fmt.Println("Error configuring DDNS:", err)
+		} else {
+			bot.Go(func(s *discordgo.Session) { runDDNS(s, provider) })
+		}
+	}
 
-	statusMsg := "Minecraft server is not running."
+	// Translate relayed chat, if configured
+	if os.Getenv("TRANSLATE_PROVIDER") != "" {
+		if translator, err = newTranslator(); err != nil {
+			fmt.Println("Error configuring translation:", err)
+		}
+	}
 
-	if err == nil { // If err is nil, it means a process was found
-		statusMsg = "Minecraft server is running."
+	// Serve the public HTTP API, if configured
+	if os.Getenv("HTTP_ADDR") != "" {
+		go serveAPI()
 	}
 
-	s.ChannelMessageSend(channelID, statusMsg)
+	// Tell systemd we are up and keep its watchdog fed
+	bot.Go(func(s *discordgo.Session) {
+		if err := sdNotify("READY=1"); err != nil {
+			fmt.Println("Error notifying systemd:", err)
+		}
+		runWatchdog(s)
+	})
+
+	// Run until CTRL-C or other term signal is received, then cleanly close down the Discord session.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	if err = bot.Run(ctx); err != nil {
+		fmt.Println("error opening connection,", explainOpenError(err))
+		os.Exit(1)
+	}
+	sdNotify("STOPPING=1")
 }
 
-func startMinecraftServer(s *discordgo.Session, m *discordgo.MessageCreate) {
-	if os.Getenv("START_COMMAND") == "" {
-		s.ChannelMessageSend(channelID, "START_COMMAND is not set in the environment")
+// This function will be called (due to AddHandler above) every time a new
+// message is created on any channel that the authenticated bot has access to.
+func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	// Handle each message once, even if Discord delivers it again
+	if !firstDelivery(m.ID) {
 		return
 	}
 
-	cmdArgs := strings.Fields(os.Getenv("START_COMMAND"))
-	cmd := exec.Command("nohup", cmdArgs...)
-	cmd.Dir = "../server"
+	// If the message is "ping" reply with "Pong!"
+	if m.Content == "ping" {
+		s.ChannelMessageSend(m.ChannelID, "Pong! github: https://github.com/hunterjsb/xn-mc?tab=readme-ov-file#xn-mc")
+	}
 
-	// Redirect output to server.out
-	stdout, err := os.Create(filepath.Join("../server", "server.out"))
-	if err != nil {
-		s.ChannelMessageSend(channelID, "Failed to create log file: "+err.Error())
+	// Relay #graveyard chat to spectators in game
+	if m.ChannelID == os.Getenv("GRAVEYARD_CHANNEL_ID") && m.Author.ID != s.State.User.ID && m.Content != "" {
+		relayToSpectators(m)
 		return
 	}
-	cmd.Stdout = stdout
-	cmd.Stderr = stdout
 
-	err = cmd.Start()
-	if err != nil {
-		s.ChannelMessageSend(channelID, "Failed to start the Minecraft server: "+err.Error())
+	// Staff can DM the bot "@player message" to whisper in game
+	if m.GuildID == "" && strings.HasPrefix(m.Content, "@") && m.Author.ID != s.State.User.ID {
+		handleWhisperDM(s, m)
 		return
 	}
 
-	s.ChannelMessageSend(channelID, "Minecraft server started.")
+	commandRouter.MessageCreate(s, m)
 }
 
-func stopMinecraftServer(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// Command to find and kill the Minecraft server process
-	cmd := exec.Command("pkill", "-f", "server.jar")
-	err := cmd.Run()
+// rconExecute runs cmd on the shared rcon connection, dialing it first if needed.
+// It is safe to call from background goroutines.
+func rconExecute(cmd string) (string, error) {
+	rconMu.Lock()
+	defer rconMu.Unlock()
 
-	if err != nil {
-		s.ChannelMessageSend(channelID, "Failed to stop the Minecraft server: "+err.Error())
-		return
+	if rconClient == nil {
+		conn, err := rcon.Dial(os.Getenv("RCON_IP"), os.Getenv("RCON_PW"))
+		if err != nil {
+			return "", fmt.Errorf("could not connect to minecraft rcon on %s: %w", os.Getenv("RCON_IP"), err)
+		}
+		rconClient = conn
 	}
 
-	s.ChannelMessageSend(channelID, "Minecraft server stopped.")
+	response, err := rconClient.Execute(cmd)
+	if err != nil {
+		// Drop the connection so the next call redials
+		rconClient.Close()
+		rconClient = nil
+	}
+	return response, err
 }
 
-var lastReadPosition int64 = 0
+func closeRcon() {
+	rconMu.Lock()
+	defer rconMu.Unlock()
 
-func streamServerLogsToDiscord(s *discordgo.Session, channelID string, logFilePath string) {
-	ticker := time.NewTicker(4 * time.Second) // Check for updates every 2 seconds
-	for range ticker.C {
-		// Open the log file
-		file, err := os.Open(logFilePath)
-		if err != nil {
-			fmt.Println("Error opening log file:", err)
-			continue
-		}
+	if rconClient != nil {
+		rconClient.Close()
+		rconClient = nil
+	}
+}
 
-		// Seek to the last read position
-		_, err = file.Seek(lastReadPosition, 0)
-		if err != nil {
-			fmt.Println("Error seeking log file:", err)
-			file.Close()
-			continue
+func executeRcon(s *discordgo.Session, m *discordgo.MessageCreate, cmd string) {
+	// Some plugins answer in the log rather than the rcon response
+	watcher := delayedOutputWatch(cmd)
+	response, err := rconExecute(cmd)
+	if err != nil {
+		if watcher != nil {
+			watcher.stop()
 		}
-
-		// Read new log entries
-		scanner := bufio.NewScanner(file)
-		var logUpdates string
-		for scanner.Scan() {
-			logUpdates += scanner.Text() + "\n"
+		s.ChannelMessageSend(replyChannel(m), "**ERROR**: "+err.Error())
+		return
+	}
+	if watcher != nil {
+		s.ChannelTyping(replyChannel(m))
+		if captured := watcher.collect(); len(captured) > 0 {
+			response = strings.TrimSpace(response + "\n" + strings.Join(captured, "\n"))
 		}
+	}
+	chunks := discordutil.SplitMessage(response, discordutil.MaxMessageLength)
+	if converted, ok := relay.ToANSI(response); ok {
+		chunks = discordutil.CodeBlocks(converted, "ansi")
+	}
+	for _, chunk := range chunks {
+		s.ChannelMessageSend(replyChannel(m), chunk)
+	}
+}
 
-		if err := scanner.Err(); err != nil {
-			fmt.Println("Error reading log file:", err)
-			file.Close()
-			continue
-		}
+// streamServerLogsToDiscord handles each console line as it arrives and relays them to
+// channelID in batches.
+func streamServerLogsToDiscord(s *discordgo.Session, channelID string) {
+	lines, err := controller.Logs(context.Background())
+	if err != nil {
+		fmt.Println("Error reading server logs:", err)
+		return
+	}
 
-		// Update the last read position
-		lastReadPosition, err = file.Seek(0, io.SeekCurrent)
-		if err != nil {
-			fmt.Println("Error getting current position in log file:", err)
-			file.Close()
-			continue
+	deaths := startLineWorker("deaths", func(line string) { postDeath(s, line) })
+	chat := startLineWorker("chat", func(line string) {
+		if !relayWhisperReply(s, line) {
+			relayChat(s, line)
+			relayGraveyardChat(s, line)
 		}
-
-		file.Close()
-
-		// Send new log entries to Discord, if any
-		if logUpdates != "" {
-			_, err = s.ChannelMessageSend(channelID, "```"+logUpdates+"```")
-			if err != nil {
-				fmt.Println("Error sending log updates to Discord:", err)
-			}
+	})
+
+	// Votes make RCON calls, which must not hold up the relay
+	votes := startLineWorker("restartvote", func(line string) { handleRestartVote(s, line) })
+
+	logRelay := relay.New(s, relay.Options{
+		ChannelID:         channelID,
+		ConsoleChannelID:  os.Getenv("CONSOLE_CHANNEL_ID"),
+		MessagesPerMinute: logMessagesPerMinute(),
+	})
+	logRelay.Run(context.Background(), lines, func(line string) {
+		recordLogEvent(line)
+		recordLogLine(line)
+		feedLogWatchers(line)
+		if _, _, ok := parseChatLine(line); ok {
+			chat.send(line)
+			votes.send(line)
 		}
-	}
+		if _, _, ok := parseDeathLine(line); ok {
+			deaths.send(line)
+		}
+	})
+}
+
+// logMessagesPerMinute caps how many messages the log relay sends per minute (LOG_MESSAGES_PER_MINUTE, default 10).
+func logMessagesPerMinute() int {
+	n, _ := strconv.Atoi(os.Getenv("LOG_MESSAGES_PER_MINUTE"))
+	return n
 }
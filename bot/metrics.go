@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	metricsInterval  = 10 * time.Minute
+	metricsRetention = 8 * 24 * time.Hour
+)
+
+// MetricsSample is a point-in-time snapshot of the server.
+type MetricsSample struct {
+	Time      time.Time
+	Online    bool
+	TPS       float64
+	Players   []string
+	WorldSize int64
+	Deaths    int
+	// SessionCycles counts Discord gateway reconnects since the bot started
+	SessionCycles int
+}
+
+type MetricsStore struct {
+	Samples   []MetricsSample
+	FirstSeen map[string]time.Time
+}
+
+var metricsMu sync.Mutex
+
+func metricsFile() string {
+	if path := os.Getenv("METRICS_FILE"); path != "" {
+		return path
+	}
+	return "metrics.json"
+}
+
+// worldPath mirrors utils/config.py: the world lives at ../server/$WORLD_NAME.
+func worldPath() string {
+	name := os.Getenv("WORLD_NAME")
+	if name == "" {
+		name = "world"
+	}
+	return filepath.Join("../server", name)
+}
+
+func loadMetrics() (MetricsStore, error) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	store := MetricsStore{FirstSeen: map[string]time.Time{}}
+	err := readJSONFile(metricsFile(), &store)
+	return store, err
+}
+
+// collectMetrics samples the server every metricsInterval and keeps metricsRetention of history.
+func collectMetrics() {
+	ticker := time.NewTicker(metricsInterval)
+	for ; true; <-ticker.C {
+		status := readServerStatus()
+		sample := MetricsSample{
+			Time:    time.Now(),
+			Online:  status.Online,
+			TPS:     status.TPS,
+			Players: status.Players,
+
+			SessionCycles: currentSessionCycles(),
+		}
+
+		var err error
+		if sample.WorldSize, err = dirSize(worldPath()); err != nil {
+			fmt.Println("Error measuring world size:", err)
+		}
+		if sample.Deaths, err = totalDeaths(); err != nil {
+			fmt.Println("Error counting deaths:", err)
+		}
+
+		if err = recordSample(sample); err != nil {
+			fmt.Println("Error saving metrics:", err)
+		}
+	}
+}
+
+func recordSample(sample MetricsSample) error {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	store := MetricsStore{FirstSeen: map[string]time.Time{}}
+	if err := readJSONFile(metricsFile(), &store); err != nil {
+		return err
+	}
+	if store.FirstSeen == nil {
+		store.FirstSeen = map[string]time.Time{}
+	}
+
+	for _, player := range sample.Players {
+		if _, ok := store.FirstSeen[player]; !ok {
+			store.FirstSeen[player] = sample.Time
+		}
+	}
+
+	cutoff := sample.Time.Add(-metricsRetention)
+	for len(store.Samples) > 0 && store.Samples[0].Time.Before(cutoff) {
+		store.Samples = store.Samples[1:]
+	}
+	store.Samples = append(store.Samples, sample)
+
+	return writeJSONFile(metricsFile(), store, 0)
+}
+
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// totalDeaths sums minecraft:deaths over every player's stats file.
+func totalDeaths() (int, error) {
+	players, err := readPlayerStats()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, player := range players {
+		total += player.Deaths
+	}
+	return total, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/moderation"
+)
+
+func casesFile() string {
+	if path := os.Getenv("CASES_FILE"); path != "" {
+		return path
+	}
+	return "cases.json"
+}
+
+// caseStore keeps the moderation cases in CASES_FILE with the bot's other stores, so they get
+// the same backups.
+type caseStore struct{}
+
+func (caseStore) Load(cases *[]moderation.Case) error {
+	return readJSONFile(casesFile(), cases)
+}
+
+func (caseStore) Save(cases []moderation.Case) error {
+	return writeJSONFile(casesFile(), cases, fileBackups())
+}
+
+// modLogChannel is where moderation actions are posted, MOD_LOG_CHANNEL_ID or the admin channel.
+func modLogChannel() string {
+	if id := os.Getenv("MOD_LOG_CHANNEL_ID"); id != "" {
+		return id
+	}
+	return adminChannel()
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// NBT tag types
+const (
+	tagEnd byte = iota
+	tagByte
+	tagShort
+	tagInt
+	tagLong
+	tagFloat
+	tagDouble
+	tagByteArray
+	tagString
+	tagList
+	tagCompound
+	tagIntArray
+	tagLongArray
+)
+
+// maxChunkSize bounds a decompressed chunk, well above what the game writes.
+const maxChunkSize = 16 << 20
+
+// nbtPayloadSizes is the fewest bytes a payload of each type can take, used to reject
+// lengths that can't fit in what is left of the document.
+var nbtPayloadSizes = map[byte]int64{
+	tagByte: 1, tagShort: 2, tagInt: 4, tagLong: 8, tagFloat: 4, tagDouble: 8, tagByteArray: 4,
+	tagString: 2, tagList: 5, tagCompound: 1, tagIntArray: 4, tagLongArray: 4,
+}
+
+// readNBT decodes an uncompressed NBT document. Compounds become map[string]any,
+// lists and arrays become slices, and numbers keep their Go type.
+func readNBT(r *bytes.Reader) (map[string]any, error) {
+	var typ byte
+	if err := binary.Read(r, binary.BigEndian, &typ); err != nil {
+		return nil, err
+	}
+	if typ != tagCompound {
+		return nil, fmt.Errorf("nbt: root is tag %d, not a compound", typ)
+	}
+	if _, err := readNBTString(r); err != nil {
+		return nil, err
+	}
+	value, err := readNBTPayload(r, typ)
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]any), nil
+}
+
+func readNBTString(r *bytes.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	buf := make([]byte, length)
+	_, err := io.ReadFull(r, buf)
+	return string(buf), err
+}
+
+// readLength reads an array or list length and checks that count elements of elemSize bytes
+// fit in the rest of r, so corrupt data can't make it allocate without bound.
+func readLength(r *bytes.Reader, elemSize int64) (int32, error) {
+	var length int32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return 0, err
+	}
+	if length < 0 || int64(length)*elemSize > int64(r.Len()) {
+		return 0, fmt.Errorf("nbt: length %d exceeds the %d bytes left", length, r.Len())
+	}
+	return length, nil
+}
+
+func readNBTPayload(r *bytes.Reader, typ byte) (any, error) {
+	switch typ {
+	case tagByte:
+		var v int8
+		err := binary.Read(r, binary.BigEndian, &v)
+		return v, err
+	case tagShort:
+		var v int16
+		err := binary.Read(r, binary.BigEndian, &v)
+		return v, err
+	case tagInt:
+		var v int32
+		err := binary.Read(r, binary.BigEndian, &v)
+		return v, err
+	case tagLong:
+		var v int64
+		err := binary.Read(r, binary.BigEndian, &v)
+		return v, err
+	case tagFloat:
+		var v uint32
+		err := binary.Read(r, binary.BigEndian, &v)
+		return math.Float32frombits(v), err
+	case tagDouble:
+		var v uint64
+		err := binary.Read(r, binary.BigEndian, &v)
+		return math.Float64frombits(v), err
+	case tagByteArray:
+		length, err := readLength(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		v := make([]byte, length)
+		_, err = io.ReadFull(r, v)
+		return v, err
+	case tagString:
+		return readNBTString(r)
+	case tagList:
+		var elemType byte
+		if err := binary.Read(r, binary.BigEndian, &elemType); err != nil {
+			return nil, err
+		}
+		// Empty lists are written with element type end
+		elemSize, known := nbtPayloadSizes[elemType]
+		if elemType == tagEnd {
+			elemSize = 1
+		} else if !known {
+			return nil, fmt.Errorf("nbt: unknown list element type %d", elemType)
+		}
+		length, err := readLength(r, elemSize)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, 0, length)
+		for i := int32(0); i < length; i++ {
+			elem, err := readNBTPayload(r, elemType)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, elem)
+		}
+		return list, nil
+	case tagCompound:
+		compound := map[string]any{}
+		for {
+			var childType byte
+			if err := binary.Read(r, binary.BigEndian, &childType); err != nil {
+				return nil, err
+			}
+			if childType == tagEnd {
+				return compound, nil
+			}
+			name, err := readNBTString(r)
+			if err != nil {
+				return nil, err
+			}
+			if compound[name], err = readNBTPayload(r, childType); err != nil {
+				return nil, err
+			}
+		}
+	case tagIntArray:
+		length, err := readLength(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		v := make([]int32, length)
+		err = binary.Read(r, binary.BigEndian, v)
+		return v, err
+	case tagLongArray:
+		length, err := readLength(r, 8)
+		if err != nil {
+			return nil, err
+		}
+		v := make([]int64, length)
+		err = binary.Read(r, binary.BigEndian, v)
+		return v, err
+	}
+	return nil, fmt.Errorf("nbt: unknown tag type %d", typ)
+}
+
+// readRegionChunks decodes every chunk stored in an Anvil (.mca) region file.
+func readRegionChunks(path string) ([]map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4096 {
+		return nil, nil
+	}
+
+	var chunks []map[string]any
+	for i := 0; i < 1024; i++ {
+		location := binary.BigEndian.Uint32(data[i*4:])
+		offset := int(location>>8) * 4096
+		if offset == 0 || offset+5 > len(data) {
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		if length < 1 || offset+4+length > len(data) {
+			continue
+		}
+		compression := data[offset+4]
+		payload := bytes.NewReader(data[offset+5 : offset+4+length])
+
+		var r io.Reader
+		switch compression {
+		case 1:
+			if r, err = gzip.NewReader(payload); err != nil {
+				return nil, err
+			}
+		case 2:
+			if r, err = zlib.NewReader(payload); err != nil {
+				return nil, err
+			}
+		case 3:
+			r = payload
+		default:
+			// External (oversized) chunks and unknown compression are skipped
+			continue
+		}
+
+		// Decompress up front so lengths can be checked against the real size
+		decompressed, err := io.ReadAll(io.LimitReader(r, maxChunkSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("%s chunk %d: %w", path, i, err)
+		}
+		if len(decompressed) > maxChunkSize {
+			return nil, fmt.Errorf("%s chunk %d: larger than %d bytes", path, i, maxChunkSize)
+		}
+		chunk, err := readNBT(bytes.NewReader(decompressed))
+		if err != nil {
+			return nil, fmt.Errorf("%s chunk %d: %w", path, i, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
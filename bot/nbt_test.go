@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readNBTFixture(t *testing.T, name string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "nbt", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readNBT(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestReadNBT(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    map[string]any
+	}{
+		{"chunk.nbt", map[string]any{
+			"DataVersion":    int32(3465),
+			"xPos":           int32(0),
+			"zPos":           int32(0),
+			"yPos":           int32(-4),
+			"Status":         "minecraft:full",
+			"InhabitedTime":  int64(1200),
+			"LastUpdate":     int64(98765),
+			"isLightOn":      int8(1),
+			"block_entities": []any{},
+			"Heightmaps":     map[string]any{"WORLD_SURFACE": []int64{1, 2}},
+			"sections": []any{
+				map[string]any{"Y": int8(-4), "BlockLight": []byte{0, 1, 2}},
+			},
+		}},
+		{"entity.nbt", map[string]any{
+			"id":       "minecraft:zombie",
+			"Pos":      []any{1.5, 64.0, -2.5},
+			"Rotation": []any{float32(90), float32(0)},
+			"Air":      int16(300),
+			"UUID":     []int32{1, -2, 3, -4},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			if got := readNBTFixture(t, test.fixture); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+// nbtDocument wraps a single named tag in an unnamed root compound.
+func nbtDocument(typ byte, payload ...any) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{tagCompound, 0, 0, typ, 0, 1, 'v'})
+	for _, v := range payload {
+		binary.Write(&buf, binary.BigEndian, v)
+	}
+	buf.WriteByte(tagEnd)
+	return buf.Bytes()
+}
+
+func TestReadNBTErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"root is not a compound", []byte{tagList, 0, 0}, "not a compound"},
+		{"negative byte array", nbtDocument(tagByteArray, int32(-1)), "length -1"},
+		{"oversized byte array", nbtDocument(tagByteArray, int32(1<<30)), "length 1073741824"},
+		{"oversized int array", nbtDocument(tagIntArray, int32(2), int32(7)), "length 2"},
+		{"oversized long array", nbtDocument(tagLongArray, int32(1), int32(7)), "length 1"},
+		{"negative list", nbtDocument(tagList, tagInt, int32(-5)), "length -5"},
+		{"oversized list", nbtDocument(tagList, tagLong, int32(1<<20)), "length 1048576"},
+		{"unknown list element type", nbtDocument(tagList, byte(42), int32(1)), "unknown list element type 42"},
+		{"unknown tag type", nbtDocument(42), "unknown tag type 42"},
+		{"truncated string", nbtDocument(tagString, uint16(10), []byte("abc")), "EOF"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readNBT(bytes.NewReader(test.data))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got error %v, want one containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestReadRegionChunks(t *testing.T) {
+	chunks, err := readRegionChunks(filepath.Join("testdata", "nbt", "r.0.0.mca"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The third chunk is stored externally and skipped
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(chunks))
+	}
+	for i, compression := range []string{"zlib", "gzip"} {
+		if x := chunks[i]["xPos"]; x != int32(i) {
+			t.Errorf("%s chunk has xPos %v, want %d", compression, x, i)
+		}
+	}
+}
+
+func TestReadRegionChunksShortFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "r.0.0.mca")
+	if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := readRegionChunks(path)
+	if err != nil || chunks != nil {
+		t.Errorf("got %v, %v, want no chunks and no error", chunks, err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var notifyMu sync.Mutex
+
+func notifyFile() string {
+	if path := os.Getenv("NOTIFY_FILE"); path != "" {
+		return path
+	}
+	return "notify.json"
+}
+
+func handleNotify(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) != 2 || args[0] != "restarts" || (args[1] != "on" && args[1] != "off") {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `notify restarts on|off`")
+		return
+	}
+
+	notifyMu.Lock()
+	defer notifyMu.Unlock()
+
+	subscribers := map[string]bool{}
+	if err := readJSONFile(notifyFile(), &subscribers); err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read notification settings: "+err.Error())
+		return
+	}
+	if args[1] == "on" {
+		subscribers[m.Author.ID] = true
+	} else {
+		delete(subscribers, m.Author.ID)
+	}
+	if err := writeJSONFile(notifyFile(), subscribers, fileBackups()); err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to save notification settings: "+err.Error())
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Restart and outage DMs are now %s for you.", args[1]))
+}
+
+// notifySubscribers DMs everyone who opted into restart and outage notifications.
+func notifySubscribers(s *discordgo.Session, message string) {
+	notifyMu.Lock()
+	subscribers := map[string]bool{}
+	err := readJSONFile(notifyFile(), &subscribers)
+	notifyMu.Unlock()
+	if err != nil {
+		fmt.Println("Error reading notification settings:", err)
+		return
+	}
+
+	for userID := range subscribers {
+		dm, err := s.UserChannelCreate(userID)
+		if err != nil {
+			fmt.Println("Error opening DM channel:", err)
+			continue
+		}
+		if _, err = s.ChannelMessageSend(dm.ID, message); err != nil {
+			fmt.Println("Error sending DM notification:", err)
+		}
+	}
+}
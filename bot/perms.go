@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// permissionNames lists the flags shown by `perms check`, in Discord's UI order.
+var permissionNames = []struct {
+	Flag int64
+	Name string
+}{
+	{discordgo.PermissionAdministrator, "Administrator"},
+	{discordgo.PermissionViewChannel, "View Channel"},
+	{discordgo.PermissionManageChannels, "Manage Channels"},
+	{discordgo.PermissionManageRoles, "Manage Permissions"},
+	{discordgo.PermissionManageWebhooks, "Manage Webhooks"},
+	{discordgo.PermissionCreateInstantInvite, "Create Invite"},
+	{discordgo.PermissionSendMessages, "Send Messages"},
+	{discordgo.PermissionSendMessagesInThreads, "Send Messages in Threads"},
+	{discordgo.PermissionCreatePublicThreads, "Create Public Threads"},
+	{discordgo.PermissionCreatePrivateThreads, "Create Private Threads"},
+	{discordgo.PermissionEmbedLinks, "Embed Links"},
+	{discordgo.PermissionAttachFiles, "Attach Files"},
+	{discordgo.PermissionAddReactions, "Add Reactions"},
+	{discordgo.PermissionUseExternalEmojis, "Use External Emoji"},
+	{discordgo.PermissionMentionEveryone, "Mention Everyone"},
+	{discordgo.PermissionManageMessages, "Manage Messages"},
+	{discordgo.PermissionManageThreads, "Manage Threads"},
+	{discordgo.PermissionReadMessageHistory, "Read Message History"},
+	{discordgo.PermissionUseSlashCommands, "Use Application Commands"},
+	{discordgo.PermissionVoiceConnect, "Connect"},
+	{discordgo.PermissionVoiceSpeak, "Speak"},
+	{discordgo.PermissionKickMembers, "Kick Members"},
+	{discordgo.PermissionBanMembers, "Ban Members"},
+	{discordgo.PermissionModerateMembers, "Timeout Members"},
+}
+
+// handlePerms explains the effective permissions of a member or role in a channel.
+func handlePerms(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	usage := "Usage: `perms check <@user|@role> <#channel>`"
+	if !isAdmin(s, m) {
+		s.ChannelMessageSend(m.ChannelID, "Only admins can check permissions.")
+		return
+	}
+	if len(args) != 3 || args[0] != "check" {
+		s.ChannelMessageSend(m.ChannelID, usage)
+		return
+	}
+	targetChannelID := strings.TrimSuffix(strings.TrimPrefix(args[2], "<#"), ">")
+
+	channel, err := s.Channel(targetChannelID)
+	if err != nil || channel.GuildID != m.GuildID {
+		s.ChannelMessageSend(m.ChannelID, "Unknown channel "+args[2])
+		return
+	}
+	guild, err := s.Guild(m.GuildID)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to fetch guild: "+err.Error())
+		return
+	}
+
+	var userID, label string
+	var roles []string
+	if roleID, found := strings.CutPrefix(args[1], "<@&"); found {
+		roles = []string{strings.TrimSuffix(roleID, ">")}
+		label = "Role " + args[1]
+	} else {
+		userID = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(args[1], "<@"), "!"), ">")
+		member, err := guildMember(s, m.GuildID, userID)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, "Unknown member "+args[1])
+			return
+		}
+		roles = member.Roles
+		label = "Member " + member.User.Username
+	}
+
+	base, effective, steps := channelPermissions(guild, channel, userID, roles)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "PERMISSIONS: %s in <#%s>\n", label, channel.ID)
+	fmt.Fprintf(&sb, "Base (roles): %s\n", formatPermissions(base))
+	for _, step := range steps {
+		sb.WriteString(step + "\n")
+	}
+	fmt.Fprintf(&sb, "Effective: %s", formatPermissions(effective))
+	s.ChannelMessageSend(m.ChannelID, sb.String())
+}
+
+// channelPermissions applies Discord's permission hierarchy: @everyone and role permissions,
+// then the @everyone, role and member overwrites of the channel. steps describes each overwrite applied.
+func channelPermissions(guild *discordgo.Guild, channel *discordgo.Channel, userID string, roles []string) (base int64, effective int64, steps []string) {
+	if userID != "" && userID == guild.OwnerID {
+		return discordgo.PermissionAll, discordgo.PermissionAll, []string{"Guild owner, all permissions"}
+	}
+
+	roleNames := map[string]string{}
+	for _, role := range guild.Roles {
+		roleNames[role.ID] = role.Name
+		if role.ID == guild.ID || slices.Contains(roles, role.ID) {
+			base |= role.Permissions
+		}
+	}
+	if base&discordgo.PermissionAdministrator != 0 {
+		return base, discordgo.PermissionAll, []string{"Administrator, overwrites ignored"}
+	}
+
+	effective = base
+	apply := func(overwrite *discordgo.PermissionOverwrite, name string) {
+		effective &^= overwrite.Deny
+		effective |= overwrite.Allow
+		steps = append(steps, fmt.Sprintf("Overwrite %s: allow %s, deny %s", name, formatPermissions(overwrite.Allow), formatPermissions(overwrite.Deny)))
+	}
+
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.ID == guild.ID {
+			apply(overwrite, "@everyone")
+		}
+	}
+
+	// Role overwrites are combined before being applied, so an allow on any role wins over a deny
+	var allow, deny int64
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeRole && overwrite.ID != guild.ID && slices.Contains(roles, overwrite.ID) {
+			allow |= overwrite.Allow
+			deny |= overwrite.Deny
+			steps = append(steps, fmt.Sprintf("Overwrite @%s: allow %s, deny %s", roleNames[overwrite.ID], formatPermissions(overwrite.Allow), formatPermissions(overwrite.Deny)))
+		}
+	}
+	effective &^= deny
+	effective |= allow
+
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeMember && overwrite.ID == userID {
+			apply(overwrite, "member")
+		}
+	}
+	return base, effective, steps
+}
+
+func formatPermissions(perms int64) string {
+	if perms&discordgo.PermissionAdministrator != 0 {
+		return "Administrator"
+	}
+	var names []string
+	for _, p := range permissionNames {
+		if perms&p.Flag != 0 {
+			names = append(names, p.Name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+var guildMemberCache = newTTLCache[*discordgo.Member](time.Minute)
+
+// guildMember looks a member up through the state, then the API, reusing API results for a minute.
+func guildMember(s *discordgo.Session, guildID string, userID string) (*discordgo.Member, error) {
+	if member, err := s.State.Member(guildID, userID); err == nil {
+		return member, nil
+	}
+	return guildMemberCache.get(guildID+"/"+userID, func() (*discordgo.Member, error) {
+		return s.GuildMember(guildID, userID)
+	})
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+)
+
+// PlayerRecord is everything the server's files say about one player.
+type PlayerRecord struct {
+	UUID        string
+	Name        string
+	Whitelisted bool
+	OpLevel     int
+	Banned      bool
+	BanReason   string
+	BannedBy    string
+	Stats       *PlayerStats
+}
+
+// playerIndex is an in-memory index over usercache.json, whitelist.json, ops.json,
+// banned-players.json and the world's stats files. It is rebuilt whenever one of them changes.
+type playerIndex struct {
+	mu        sync.Mutex
+	signature string
+	byUUID    map[string]*PlayerRecord
+}
+
+var knownPlayers playerIndex
+
+// indexFiles lists the files the index is built from.
+func indexFiles() []string {
+	files := []string{
+		"../server/usercache.json",
+		"../server/whitelist.json",
+		"../server/ops.json",
+		"../server/banned-players.json",
+	}
+	stats, _ := filepath.Glob(filepath.Join(worldPath(), "stats", "*.json"))
+	return append(files, stats...)
+}
+
+// refresh rebuilds the index if any file was added, removed or modified since the last build.
+// The caller must hold x.mu.
+func (x *playerIndex) refresh() error {
+	files := indexFiles()
+	var sb strings.Builder
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&sb, "%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	if x.byUUID != nil && sb.String() == x.signature {
+		return nil
+	}
+
+	byUUID := map[string]*PlayerRecord{}
+	record := func(uuid, name string) *PlayerRecord {
+		r, ok := byUUID[uuid]
+		if !ok {
+			r = &PlayerRecord{UUID: uuid}
+			byUUID[uuid] = r
+		}
+		if name != "" {
+			r.Name = name
+		}
+		return r
+	}
+
+	var entries []struct {
+		UUID   string `json:"uuid"`
+		Name   string `json:"name"`
+		Level  int    `json:"level"`
+		Source string `json:"source"`
+		Reason string `json:"reason"`
+	}
+	for _, file := range files[:4] {
+		entries = nil
+		if err := readJSONFile(file, &entries); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		for _, entry := range entries {
+			r := record(entry.UUID, entry.Name)
+			switch filepath.Base(file) {
+			case "whitelist.json":
+				r.Whitelisted = true
+			case "ops.json":
+				r.OpLevel = entry.Level
+			case "banned-players.json":
+				r.Banned, r.BanReason, r.BannedBy = true, entry.Reason, entry.Source
+			}
+		}
+	}
+
+	for _, file := range files[4:] {
+		var stats struct {
+			Stats map[string]map[string]int `json:"stats"`
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, &stats); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		r := record(strings.TrimSuffix(filepath.Base(file), ".json"), "")
+		r.Stats = &PlayerStats{
+			UUID:     r.UUID,
+			Deaths:   stats.Stats["minecraft:custom"]["minecraft:deaths"],
+			PlayTime: stats.Stats["minecraft:custom"]["minecraft:play_time"],
+		}
+	}
+	for _, r := range byUUID {
+		if r.Stats != nil {
+			r.Stats.Name = r.Name
+		}
+	}
+
+	x.byUUID = byUUID
+	x.signature = sb.String()
+	return nil
+}
+
+// all returns every known player sorted by name.
+func (x *playerIndex) all() ([]PlayerRecord, error) {
+	return x.filter(func(PlayerRecord) bool { return true })
+}
+
+func (x *playerIndex) filter(keep func(PlayerRecord) bool) ([]PlayerRecord, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if err := x.refresh(); err != nil {
+		return nil, err
+	}
+
+	var res []PlayerRecord
+	for _, r := range x.byUUID {
+		if keep(*r) {
+			res = append(res, *r)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return strings.ToLower(res[i].Name) < strings.ToLower(res[j].Name) })
+	return res, nil
+}
+
+// lookup finds a player by exact name (case-insensitive) or UUID.
+func (x *playerIndex) lookup(nameOrUUID string) (PlayerRecord, bool, error) {
+	matches, err := x.filter(func(r PlayerRecord) bool {
+		return strings.EqualFold(r.Name, nameOrUUID) || r.UUID == nameOrUUID
+	})
+	if err != nil || len(matches) == 0 {
+		return PlayerRecord{}, false, err
+	}
+	return matches[0], true, nil
+}
+
+// search returns the players whose name starts with prefix, for suggestions.
+func (x *playerIndex) search(prefix string) ([]PlayerRecord, error) {
+	prefix = strings.ToLower(prefix)
+	return x.filter(func(r PlayerRecord) bool { return strings.HasPrefix(strings.ToLower(r.Name), prefix) })
+}
+
+func (x *playerIndex) banned() ([]PlayerRecord, error) {
+	return x.filter(func(r PlayerRecord) bool { return r.Banned })
+}
+
+// handleWhois describes a player, suggesting names when there is no exact match.
+func handleWhois(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) != 1 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `whois <player>`")
+		return
+	}
+	player, ok, err := knownPlayers.lookup(args[0])
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read player data: "+err.Error())
+		return
+	}
+	if !ok {
+		suggestions, _ := knownPlayers.search(args[0])
+		if len(suggestions) == 0 {
+			s.ChannelMessageSend(m.ChannelID, "No player named "+args[0])
+			return
+		}
+		names := make([]string, 0, len(suggestions))
+		for i, suggestion := range suggestions {
+			if i == 10 {
+				break
+			}
+			names = append(names, suggestion.Name)
+		}
+		s.ChannelMessageSend(m.ChannelID, "No exact match. Did you mean: "+strings.Join(names, ", "))
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "PLAYER: %s\nUUID: %s\n", player.Name, player.UUID)
+	fmt.Fprintf(&sb, "Whitelisted: %t\n", player.Whitelisted)
+	if player.OpLevel > 0 {
+		fmt.Fprintf(&sb, "Op level: %d\n", player.OpLevel)
+	}
+	if player.Banned {
+		fmt.Fprintf(&sb, "Banned by %s: %s\n", player.BannedBy, player.BanReason)
+	}
+	if player.Stats != nil {
+		fmt.Fprintf(&sb, "Deaths: %d\nPlay time: %.1f hours\n", player.Stats.Deaths, float64(player.Stats.PlayTime)/20/3600)
+	}
+	s.ChannelMessageSend(m.ChannelID, sb.String())
+}
+
+func showBans(s *discordgo.Session, m *discordgo.MessageCreate) {
+	banned, err := knownPlayers.banned()
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read bans: "+err.Error())
+		return
+	}
+	if len(banned) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No banned players.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("BANS:\n")
+	for _, player := range banned {
+		fmt.Fprintf(&sb, "%s by %s: %s\n", player.Name, player.BannedBy, player.BanReason)
+	}
+	for _, chunk := range discordutil.SplitMessage(sb.String(), discordutil.MaxMessageLength) {
+		s.ChannelMessageSend(m.ChannelID, chunk)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const probeStaleAfter = 5 * time.Minute
+
+// ProbeReport is what an external probe sends after trying to reach the Minecraft port.
+type ProbeReport struct {
+	Name      string    `json:"name"`
+	Target    string    `json:"target"`
+	Reachable bool      `json:"reachable"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+var (
+	probeReports   = map[string]ProbeReport{}
+	probeReportsMu sync.Mutex
+)
+
+// runProbe is the --probe mode: dial PROBE_TARGET every minute and report to PROBE_REPORT_URL.
+func runProbe() {
+	target, reportURL := os.Getenv("PROBE_TARGET"), os.Getenv("PROBE_REPORT_URL")
+	if target == "" || reportURL == "" {
+		fmt.Println("PROBE_TARGET and PROBE_REPORT_URL must be set in probe mode")
+		return
+	}
+	name := os.Getenv("PROBE_NAME")
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+
+	fmt.Println("Probing", target, "and reporting to", reportURL)
+	client := http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(time.Minute)
+	for ; true; <-ticker.C {
+		report := ProbeReport{Name: name, Target: target, Time: time.Now()}
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", target, 10*time.Second)
+		if err != nil {
+			report.Error = err.Error()
+		} else {
+			report.Reachable = true
+			report.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+			conn.Close()
+		}
+
+		body, _ := json.Marshal(report)
+		req, err := http.NewRequest(http.MethodPost, reportURL, bytes.NewReader(body))
+		if err != nil {
+			fmt.Println("Error creating probe report:", err)
+			continue
+		}
+		req.Header.Set("Authorization", "Bearer "+os.Getenv("PROBE_TOKEN"))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			fmt.Println("Error sending probe report:", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			fmt.Println("Probe report rejected:", resp.Status)
+		}
+	}
+}
+
+// handleProbeReport accepts reports from probes holding PROBE_TOKEN.
+func handleProbeReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := os.Getenv("PROBE_TOKEN")
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var report ProbeReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil || report.Name == "" {
+		http.Error(w, "invalid report", http.StatusBadRequest)
+		return
+	}
+	report.Time = time.Now()
+
+	probeReportsMu.Lock()
+	probeReports[report.Name] = report
+	probeReportsMu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// showProbes lists the latest probe reports and whether the server is down for everyone.
+func showProbes(s *discordgo.Session, m *discordgo.MessageCreate) {
+	probeReportsMu.Lock()
+	reports := make([]ProbeReport, 0, len(probeReports))
+	for _, report := range probeReports {
+		reports = append(reports, report)
+	}
+	probeReportsMu.Unlock()
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+
+	running := serverRunning()
+
+	var sb strings.Builder
+	sb.WriteString("PROBES:\n")
+	if len(reports) == 0 {
+		sb.WriteString("No probes have reported.\n")
+	}
+	unreachable := 0
+	for _, report := range reports {
+		switch {
+		case time.Since(report.Time) > probeStaleAfter:
+			fmt.Fprintf(&sb, "%s: stale, last report <t:%d:R>\n", report.Name, report.Time.Unix())
+		case report.Reachable:
+			fmt.Fprintf(&sb, "%s: reachable (%.0f ms)\n", report.Name, report.LatencyMS)
+		default:
+			unreachable++
+			fmt.Fprintf(&sb, "%s: unreachable (%s)\n", report.Name, report.Error)
+		}
+	}
+
+	switch {
+	case !running:
+		sb.WriteString("The server process is not running, it is down for everyone.")
+	case unreachable > 0:
+		sb.WriteString("The server is running locally but unreachable from outside, check the network and port forwarding.")
+	case len(reports) > 0:
+		sb.WriteString("The server is up and reachable from outside.")
+	}
+	s.ChannelMessageSend(m.ChannelID, sb.String())
+}
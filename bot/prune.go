@@ -0,0 +1,175 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// dimensionDirs maps a prunable dimension to its directory inside the world.
+var dimensionDirs = map[string]string{
+	"nether": "DIM-1",
+	"end":    "DIM1",
+}
+
+// regionKinds are the per-dimension folders that share region file names.
+var regionKinds = []string{"region", "entities", "poi"}
+
+func handlePrune(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	usage := "Usage: `prune regions <days> <nether|end> [confirm]`"
+	if !isAdmin(s, m) {
+		s.ChannelMessageSend(m.ChannelID, "Only admins can prune regions.")
+		return
+	}
+	if len(args) < 3 || args[0] != "regions" {
+		s.ChannelMessageSend(m.ChannelID, usage)
+		return
+	}
+	days, err := strconv.Atoi(args[1])
+	if err != nil || days <= 0 {
+		s.ChannelMessageSend(m.ChannelID, usage)
+		return
+	}
+	dimension, ok := dimensionDirs[args[2]]
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, usage)
+		return
+	}
+	confirm := len(args) > 3 && args[3] == "confirm"
+
+	files, size, err := staleRegions(filepath.Join(worldPath(), dimension), time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to scan regions: "+err.Error())
+		return
+	}
+	// Each stale region can have entities and poi files alongside it
+	perKind := map[string]int{}
+	for _, file := range files {
+		perKind[filepath.Dir(file)]++
+	}
+	summary := fmt.Sprintf("%d regions untouched for %d days in the %s (%d region, %d entities and %d poi files, %.3f GB)",
+		perKind["region"], days, args[2], perKind["region"], perKind["entities"], perKind["poi"], float64(size)/1000000000)
+	if !confirm || len(files) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "DRY RUN: "+summary+". Add `confirm` to archive and delete them.")
+		return
+	}
+
+	if serverRunning() {
+		s.ChannelMessageSend(m.ChannelID, "Stop the server before pruning regions.")
+		return
+	}
+
+	ctx, done, err := startTask("prune")
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, err.Error())
+		return
+	}
+	defer done()
+
+	archive := filepath.Join("../server/region-archive", fmt.Sprintf("%s-%s.zip", args[2], time.Now().Format("20060102-150405")))
+	if err = archiveAndDelete(ctx, archive, filepath.Join(worldPath(), dimension), files); err != nil {
+		if ctx.Err() != nil {
+			s.ChannelMessageSend(m.ChannelID, taskError(ctx, "prune")+" No regions were deleted.")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "Failed to prune regions: "+err.Error())
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Pruned %s. Archived to %s.", summary, archive))
+}
+
+// staleRegions lists region files (relative to dimDir) whose region, entities and poi
+// files have all not been modified since cutoff.
+func staleRegions(dimDir string, cutoff time.Time) (files []string, size int64, err error) {
+	regions, err := filepath.Glob(filepath.Join(dimDir, "region", "*.mca"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, region := range regions {
+		name := filepath.Base(region)
+		stale := true
+		var group []string
+		var groupSize int64
+		for _, kind := range regionKinds {
+			info, err := os.Stat(filepath.Join(dimDir, kind, name))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+			if info.ModTime().After(cutoff) {
+				stale = false
+				break
+			}
+			group = append(group, filepath.Join(kind, name))
+			groupSize += info.Size()
+		}
+		if stale {
+			files = append(files, group...)
+			size += groupSize
+		}
+	}
+	return files, size, nil
+}
+
+// archiveAndDelete zips files (relative to dir) into archive and removes them once the zip is
+// complete. Cancelling ctx while zipping removes the partial archive and deletes nothing.
+func archiveAndDelete(ctx context.Context, archive string, dir string, files []string) error {
+	if err := os.MkdirAll(filepath.Dir(archive), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for _, file := range files {
+		if err = ctx.Err(); err != nil {
+			out.Close()
+			os.Remove(archive)
+			return err
+		}
+		if err = addToZip(zw, dir, file); err != nil {
+			return err
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err = os.Remove(filepath.Join(dir, file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addToZip(zw *zip.Writer, dir string, file string) error {
+	in, err := os.Open(filepath.Join(dir, file))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	w, err := zw.Create(filepath.ToSlash(file))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, in)
+	return err
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const restartHistorySize = 10
+
+// Restart records who stopped or restarted the server and why.
+type Restart struct {
+	Time     time.Time
+	Action   string
+	UserID   string
+	Username string
+	Reason   string
+}
+
+var restartsMu sync.Mutex
+
+func restartsFile() string {
+	if path := os.Getenv("RESTARTS_FILE"); path != "" {
+		return path
+	}
+	return "restarts.json"
+}
+
+func recordRestart(m *discordgo.MessageCreate, action string, reason string) {
+	appendRestart(Restart{
+		Time:     time.Now(),
+		Action:   action,
+		UserID:   m.Author.ID,
+		Username: m.Author.Username,
+		Reason:   reason,
+	})
+}
+
+func appendRestart(restart Restart) {
+	restartsMu.Lock()
+	defer restartsMu.Unlock()
+
+	var restarts []Restart
+	if err := readJSONFile(restartsFile(), &restarts); err != nil {
+		fmt.Println("Error reading restart history:", err)
+	}
+	restarts = append(restarts, restart)
+	if err := writeJSONFile(restartsFile(), restarts, fileBackups()); err != nil {
+		fmt.Println("Error saving restart history:", err)
+	}
+}
+
+func showRestartHistory(s *discordgo.Session, m *discordgo.MessageCreate) {
+	restartsMu.Lock()
+	var restarts []Restart
+	err := readJSONFile(restartsFile(), &restarts)
+	restartsMu.Unlock()
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read restart history: "+err.Error())
+		return
+	}
+	if len(restarts) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "No restarts recorded.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("RESTARTS:\n")
+	for i := len(restarts) - 1; i >= 0 && i >= len(restarts)-restartHistorySize; i-- {
+		restart := restarts[i]
+		fmt.Fprintf(&sb, "<t:%d:f> %s by %s: %s\n", restart.Time.Unix(), restart.Action, restart.Username, restart.Reason)
+	}
+	s.ChannelMessageSend(m.ChannelID, sb.String())
+}
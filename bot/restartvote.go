@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const restartVoteWindow = 5 * time.Minute
+
+// RestartVote is an in-game poll to restart the server while TPS is degraded.
+type RestartVote struct {
+	Started time.Time
+	Voters  map[string]bool
+	Needed  int
+}
+
+var (
+	restartVote   *RestartVote
+	restartVoteMu sync.Mutex
+)
+
+// restartVoteMaxTPS is the TPS below which players may call a vote (RESTART_VOTE_MAX_TPS, default 15).
+func restartVoteMaxTPS() float64 {
+	if tps, err := strconv.ParseFloat(os.Getenv("RESTART_VOTE_MAX_TPS"), 64); err == nil {
+		return tps
+	}
+	return 15
+}
+
+// restartVoteQuorum is the fraction of online players that must agree (RESTART_VOTE_QUORUM, default 0.5).
+func restartVoteQuorum() float64 {
+	if quorum, err := strconv.ParseFloat(os.Getenv("RESTART_VOTE_QUORUM"), 64); err == nil && quorum > 0 && quorum <= 1 {
+		return quorum
+	}
+	return 0.5
+}
+
+// handleRestartVote counts `!restartvote` chat messages and restarts the server once quorum is reached.
+func handleRestartVote(s *discordgo.Session, line string) {
+	player, message, ok := parseChatLine(line)
+	if !ok || strings.TrimSpace(message) != "!restartvote" {
+		return
+	}
+
+	restartVoteMu.Lock()
+	defer restartVoteMu.Unlock()
+
+	if restartVote == nil {
+		tps, err := readTPS()
+		if err != nil {
+			fmt.Println("Error reading TPS for restart vote:", err)
+			return
+		}
+		if tps >= restartVoteMaxTPS() {
+			rconExecute(fmt.Sprintf("tellraw %s {\"text\":\"TPS is %.1f, no restart needed.\",\"color\":\"gray\"}", player, tps))
+			return
+		}
+		players, _, err := onlinePlayers()
+		if err != nil {
+			fmt.Println("Error listing players for restart vote:", err)
+			return
+		}
+
+		vote := &RestartVote{
+			Started: time.Now(),
+			Voters:  map[string]bool{},
+			Needed:  int(math.Ceil(float64(len(players)) * restartVoteQuorum())),
+		}
+		restartVote = vote
+		rconExecute(fmt.Sprintf("say %s started a vote to restart the server (TPS %.1f). Type !restartvote within 5 minutes to agree.", player, tps))
+		s.ChannelMessageSend(channelID, fmt.Sprintf("**RESTART VOTE**: started by %s at TPS %.1f, %d vote(s) needed", player, tps, vote.Needed))
+		time.AfterFunc(restartVoteWindow, func() { expireRestartVote(s, vote) })
+	}
+
+	if restartVote.Voters[player] {
+		return
+	}
+	restartVote.Voters[player] = true
+	votes := len(restartVote.Voters)
+	if votes < restartVote.Needed {
+		rconExecute(fmt.Sprintf("say Restart vote: %d/%d", votes, restartVote.Needed))
+		return
+	}
+
+	reason := fmt.Sprintf("restart vote passed %d/%d", votes, restartVote.Needed)
+	restartVote = nil
+	s.ChannelMessageSend(channelID, "**RESTART VOTE**: "+reason)
+	go gracefulRestart(s, reason)
+}
+
+// expireRestartVote closes vote if it is still open after the voting window.
+func expireRestartVote(s *discordgo.Session, vote *RestartVote) {
+	restartVoteMu.Lock()
+	defer restartVoteMu.Unlock()
+	if restartVote != vote {
+		return
+	}
+	restartVote = nil
+
+	rconExecute("say Restart vote failed.")
+	s.ChannelMessageSend(channelID, fmt.Sprintf("**RESTART VOTE**: failed with %d/%d vote(s)", len(vote.Voters), vote.Needed))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var rulesMu sync.Mutex
+
+func rulesAckFile() string {
+	if path := os.Getenv("RULES_ACK_FILE"); path != "" {
+		return path
+	}
+	return "rules_ack.json"
+}
+
+func rulesEmoji() string {
+	if emoji := os.Getenv("RULES_EMOJI"); emoji != "" {
+		return emoji
+	}
+	return theme.RulesEmoji
+}
+
+// setupRules seeds the acknowledgment reaction on RULES_MESSAGE_ID so members only have to click it.
+func setupRules(s *discordgo.Session) {
+	err := s.MessageReactionAdd(os.Getenv("RULES_CHANNEL_ID"), os.Getenv("RULES_MESSAGE_ID"), rulesEmoji())
+	if err != nil {
+		fmt.Println("Error adding rules reaction:", err)
+	}
+}
+
+// rulesReactionAdd gives PLAYER_ROLE_ID to members who react to the rules message and records when they did.
+func rulesReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if r.MessageID != os.Getenv("RULES_MESSAGE_ID") || r.Emoji.Name != rulesEmoji() || r.UserID == s.State.User.ID {
+		return
+	}
+
+	rulesMu.Lock()
+	acks := map[string]time.Time{}
+	err := readJSONFile(rulesAckFile(), &acks)
+	_, seen := acks[r.UserID]
+	if err == nil && !seen {
+		acks[r.UserID] = time.Now()
+		err = writeJSONFile(rulesAckFile(), acks, fileBackups())
+	}
+	rulesMu.Unlock()
+	if err != nil {
+		fmt.Println("Error recording rules acknowledgment:", err)
+	}
+
+	if err = s.GuildMemberRoleAdd(r.GuildID, r.UserID, os.Getenv("PLAYER_ROLE_ID")); err != nil {
+		fmt.Println("Error assigning player role:", err)
+		return
+	}
+	if seen {
+		return
+	}
+
+	dm, err := s.UserChannelCreate(r.UserID)
+	if err != nil {
+		fmt.Println("Error opening DM:", err)
+		return
+	}
+	s.ChannelMessageSend(dm.ID, fmt.Sprintf("Thanks for accepting the rules! You can now chat in the server. Join us in game at `%s`.", dnsHostname()))
+}
+
+// handleRules shows when a member acknowledged the rules.
+func handleRules(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if !isAdmin(s, m) {
+		s.ChannelMessageSend(m.ChannelID, "Only admins can look up rules acknowledgments.")
+		return
+	}
+	if len(args) != 2 || args[0] != "ack" {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `rules ack @member`")
+		return
+	}
+	userID := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(args[1], "<@"), "!"), ">")
+
+	rulesMu.Lock()
+	acks := map[string]time.Time{}
+	err := readJSONFile(rulesAckFile(), &acks)
+	rulesMu.Unlock()
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to read rules acknowledgments: "+err.Error())
+		return
+	}
+
+	ack, ok := acks[userID]
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("<@%s> has not acknowledged the rules.", userID))
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("<@%s> acknowledged the rules <t:%d:f>.", userID, ack.Unix()))
+}
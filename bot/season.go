@@ -0,0 +1,88 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// PlayerStats is the part of a player's stats file the season dashboard uses.
+type PlayerStats struct {
+	UUID     string `json:"uuid"`
+	Name     string `json:"name"`
+	Deaths   int    `json:"deaths"`
+	PlayTime int    `json:"play_time_ticks"`
+}
+
+// SeasonStats aggregates the current hardcore season. Players are alive until their first death.
+type SeasonStats struct {
+	Alive           int          `json:"alive"`
+	Dead            int          `json:"dead"`
+	TotalDeaths     int          `json:"total_deaths"`
+	LongestSurvivor *PlayerStats `json:"longest_survivor,omitempty"`
+	KillFeed        []Event      `json:"kill_feed"`
+}
+
+// readPlayerStats returns the stats of every player with a stats file, from the player index.
+func readPlayerStats() ([]PlayerStats, error) {
+	records, err := knownPlayers.all()
+	if err != nil {
+		return nil, err
+	}
+
+	var players []PlayerStats
+	for _, record := range records {
+		if record.Stats != nil {
+			players = append(players, *record.Stats)
+		}
+	}
+	return players, nil
+}
+
+func readSeasonStats() (SeasonStats, error) {
+	players, err := readPlayerStats()
+	if err != nil {
+		return SeasonStats{}, err
+	}
+
+	season := SeasonStats{KillFeed: []Event{}}
+	for i, player := range players {
+		season.TotalDeaths += player.Deaths
+		if player.Deaths > 0 {
+			season.Dead++
+			continue
+		}
+		season.Alive++
+		if season.LongestSurvivor == nil || player.PlayTime > season.LongestSurvivor.PlayTime {
+			season.LongestSurvivor = &players[i]
+		}
+	}
+	for _, event := range recentEvents() {
+		if event.Type == "death" {
+			season.KillFeed = append(season.KillFeed, event)
+		}
+	}
+	return season, nil
+}
+
+// handleSeasonJSON serves the season statistics to holders of STATS_TOKEN.
+func handleSeasonJSON(w http.ResponseWriter, r *http.Request) {
+	token := os.Getenv("STATS_TOKEN")
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	season, err := readSeasonStats()
+	if err != nil {
+		fmt.Println("Error reading season stats:", err)
+		http.Error(w, "failed to read stats", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(season)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/monitor"
+)
+
+const serverInfoHeader = "SERVER INFO"
+
+func minecraftAddress() string {
+	if address := os.Getenv("MC_ADDRESS"); address != "" {
+		return address
+	}
+	return "localhost:25565"
+}
+
+// syncServerInfo keeps a pinned message in SERVER_INFO_CHANNEL_ID with the address, version,
+// player count, last restart and map link of the server.
+func syncServerInfo(s *discordgo.Session) {
+	infoChannelID := os.Getenv("SERVER_INFO_CHANNEL_ID")
+
+	interval := 5 * time.Minute
+	if raw := os.Getenv("SERVER_INFO_INTERVAL"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			fmt.Println("Invalid SERVER_INFO_INTERVAL, using default:", err)
+		} else {
+			interval = parsed
+		}
+	}
+
+	messageID := findPinnedMessage(s, infoChannelID, serverInfoHeader)
+	ticker := time.NewTicker(interval)
+	for ; true; <-ticker.C {
+		content := formatServerInfo()
+
+		if messageID != "" {
+			_, err := s.ChannelMessageEdit(infoChannelID, messageID, content)
+			if err == nil {
+				continue
+			}
+			// The message was probably deleted, post a new one
+			fmt.Println("Error editing server info message:", err)
+		}
+
+		msg, err := s.ChannelMessageSend(infoChannelID, content)
+		if err != nil {
+			fmt.Println("Error sending server info message:", err)
+			continue
+		}
+		messageID = msg.ID
+		if err = s.ChannelMessagePin(infoChannelID, messageID); err != nil {
+			fmt.Println("Error pinning server info message:", err)
+		}
+	}
+}
+
+func formatServerInfo() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s\n", serverInfoHeader, serverName())
+	fmt.Fprintf(&sb, "IP: `%s`\n", dnsHostname())
+
+	if ping, err := monitor.Ping(minecraftAddress()); err == nil {
+		fmt.Fprintf(&sb, "Version: %s\n", ping.Version.Name)
+		fmt.Fprintf(&sb, "Online: %d/%d\n", ping.Players.Online, ping.Players.Max)
+	} else {
+		sb.WriteString("Status: offline\n")
+	}
+
+	restartsMu.Lock()
+	var restarts []Restart
+	err := readJSONFile(restartsFile(), &restarts)
+	restartsMu.Unlock()
+	if err == nil && len(restarts) > 0 {
+		last := restarts[len(restarts)-1]
+		fmt.Fprintf(&sb, "Last restart: %s (%s)\n", timestamp(last.Time), last.Action)
+	}
+
+	if mapURL := os.Getenv("MAP_URL"); mapURL != "" {
+		fmt.Fprintf(&sb, "Map: %s\n", mapURL)
+	}
+	sb.WriteString("Updated " + timestamp(time.Now()))
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	sessionMu      sync.Mutex
+	disconnectedAt time.Time
+	sessionCycles  int
+)
+
+// onDisconnect remembers when the gateway session dropped. discordgo reconnects on its own.
+func onDisconnect(s *discordgo.Session, d *discordgo.Disconnect) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	if disconnectedAt.IsZero() {
+		disconnectedAt = time.Now()
+		fmt.Println("Discord session disconnected, waiting for reconnect")
+	}
+}
+
+func onResumed(s *discordgo.Session, r *discordgo.Resumed) {
+	sessionRestored(s, "resumed")
+}
+
+func onReady(s *discordgo.Session, r *discordgo.Ready) {
+	sessionRestored(s, "reconnected with a new session")
+}
+
+func currentSessionCycles() int {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return sessionCycles
+}
+
+// sessionRestored counts a session cycle and tells admins how long the bot was away.
+// The first Ready after startup is not a cycle.
+func sessionRestored(s *discordgo.Session, how string) {
+	sessionMu.Lock()
+	if disconnectedAt.IsZero() {
+		sessionMu.Unlock()
+		return
+	}
+	downtime := time.Since(disconnectedAt).Round(time.Second)
+	disconnectedAt = time.Time{}
+	sessionCycles++
+	cycles := sessionCycles
+	sessionMu.Unlock()
+
+	fmt.Printf("Discord session %s after %s\n", how, downtime)
+	s.ChannelMessageSend(adminChannel(), fmt.Sprintf("**DISCORD**: session %s after %s offline (%d reconnects since startup).", how, downtime, cycles))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+)
+
+var starboardMu sync.Mutex
+
+func starboardFile() string {
+	if path := os.Getenv("STARBOARD_FILE"); path != "" {
+		return path
+	}
+	return "starboard.json"
+}
+
+// starboardThresholds are the watched channels and the stars a message in each needs, from
+// STARBOARD_CHANNELS ("channelID:threshold,..."), parsed by loadConfig.
+var starboardThresholds = map[string]int{}
+
+func parseStarboardThresholds(raw string) map[string]int {
+	thresholds := map[string]int{}
+	for channel, value := range parseKeyValues("STARBOARD_CHANNELS", raw) {
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold <= 0 {
+			fmt.Printf("Ignoring STARBOARD_CHANNELS entry for %s: invalid threshold %q\n", channel, value)
+			continue
+		}
+		thresholds[channel] = threshold
+	}
+	return thresholds
+}
+
+// starboardThreshold is the number of stars a message in channel needs, or 0 if the channel is not watched.
+func starboardThreshold(channel string) int {
+	return starboardThresholds[channel]
+}
+
+// starboardReactionAdd reposts messages that reach their channel's star threshold to
+// STARBOARD_CHANNEL_ID, and keeps the star count of reposts up to date.
+func starboardReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if r.Emoji.Name != theme.StarEmoji {
+		return
+	}
+	threshold := starboardThreshold(r.ChannelID)
+	if threshold == 0 {
+		return
+	}
+
+	msg, err := s.ChannelMessage(r.ChannelID, r.MessageID)
+	if err != nil {
+		fmt.Println("Error fetching starred message:", err)
+		return
+	}
+	stars := 0
+	for _, reaction := range msg.Reactions {
+		if reaction.Emoji.Name == theme.StarEmoji {
+			stars = reaction.Count
+		}
+	}
+	if stars < threshold {
+		return
+	}
+
+	starboardMu.Lock()
+	defer starboardMu.Unlock()
+
+	// Message ID to the ID of its repost
+	posted := map[string]string{}
+	if err = readJSONFile(starboardFile(), &posted); err != nil {
+		fmt.Println("Error reading starboard:", err)
+		return
+	}
+
+	starboardChannelID := os.Getenv("STARBOARD_CHANNEL_ID")
+	content := formatStarboardPost(msg, r.GuildID, stars)
+	if postID, ok := posted[msg.ID]; ok {
+		if _, err = s.ChannelMessageEdit(starboardChannelID, postID, content); err != nil {
+			fmt.Println("Error updating starboard post:", err)
+		}
+		return
+	}
+
+	post, err := s.ChannelMessageSend(starboardChannelID, content)
+	if err != nil {
+		fmt.Println("Error posting to starboard:", err)
+		return
+	}
+	posted[msg.ID] = post.ID
+	if err = writeJSONFile(starboardFile(), posted, fileBackups()); err != nil {
+		fmt.Println("Error saving starboard:", err)
+	}
+}
+
+func formatStarboardPost(msg *discordgo.Message, guildID string, stars int) string {
+	header := fmt.Sprintf("%s **%d** <#%s> by <@%s>\n", theme.StarEmoji, stars, msg.ChannelID, msg.Author.ID)
+	footer := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, msg.ChannelID, msg.ID)
+	for _, attachment := range msg.Attachments {
+		footer = attachment.URL + "\n" + footer
+	}
+
+	// Trim the quoted text so the attribution and links always fit
+	body := []rune(msg.Content)
+	if room := discordutil.MaxMessageLength - len([]rune(header+footer)) - 2; len(body) > room {
+		body = append(body[:max(room-1, 0)], '…')
+	}
+	if len(body) == 0 {
+		return header + footer
+	}
+	return header + string(body) + "\n" + footer
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	listRegex     = regexp.MustCompile(`There are (\d+) of a max of (\d+) players online:(.*)`)
+	tickRateRegex = regexp.MustCompile(`Target tick rate: ([\d.]+)`)
+	msptRegex     = regexp.MustCompile(`Average time per tick: ([\d.]+)ms`)
+)
+
+type ServerStatus struct {
+	Name       string
+	Online     bool
+	Players    []string
+	MaxPlayers int
+	TPS        float64
+	Uptime     time.Duration
+}
+
+func serverName() string {
+	if name := os.Getenv("SERVER_NAME"); name != "" {
+		return name
+	}
+	return "xn-mc"
+}
+
+// serverPID returns the PID of the running server.jar process.
+func serverPID() (int, error) {
+	out, err := exec.Command("pgrep", "-f", "server.jar").Output()
+	if err != nil {
+		return 0, fmt.Errorf("minecraft server is not running")
+	}
+	return strconv.Atoi(strings.Fields(string(out))[0])
+}
+
+func processUptime(pid int) (time.Duration, error) {
+	out, err := exec.Command("ps", "-o", "etimes=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return 0, err
+	}
+	seconds, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// onlinePlayers parses the response of the `list` command.
+func onlinePlayers() (players []string, maxPlayers int, err error) {
+	response, err := rconExecute("list")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	match := listRegex.FindStringSubmatch(response)
+	if match == nil {
+		return nil, 0, fmt.Errorf("unexpected list response: %q", response)
+	}
+	maxPlayers, _ = strconv.Atoi(match[2])
+	for _, name := range strings.Split(match[3], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			players = append(players, name)
+		}
+	}
+	return players, maxPlayers, nil
+}
+
+// readTPS derives ticks per second from `tick query`, capped at the target tick rate.
+func readTPS() (float64, error) {
+	response, err := rconExecute("tick query")
+	if err != nil {
+		return 0, err
+	}
+
+	rateMatch := tickRateRegex.FindStringSubmatch(response)
+	msptMatch := msptRegex.FindStringSubmatch(response)
+	if rateMatch == nil || msptMatch == nil {
+		return 0, fmt.Errorf("unexpected tick query response: %q", response)
+	}
+	rate, _ := strconv.ParseFloat(rateMatch[1], 64)
+	mspt, _ := strconv.ParseFloat(msptMatch[1], 64)
+	if mspt <= 0 {
+		return rate, nil
+	}
+	return min(rate, 1000/mspt), nil
+}
+
+var serverStatusCache = newTTLCache[ServerStatus](10 * time.Second)
+
+// readServerStatus returns the server status, reusing a reading from the last 10 seconds.
+func readServerStatus() ServerStatus {
+	status, _ := serverStatusCache.get("", func() (ServerStatus, error) { return loadServerStatus(), nil })
+	return status
+}
+
+func loadServerStatus() ServerStatus {
+	status := ServerStatus{Name: serverName()}
+
+	stats, err := controller.Stats(context.Background())
+	if err != nil {
+		fmt.Println("Error reading server stats:", err)
+	}
+	if !stats.Running {
+		return status
+	}
+	status.Online = true
+	status.Uptime = stats.Uptime
+
+	if status.Players, status.MaxPlayers, err = onlinePlayers(); err != nil {
+		fmt.Println("Error reading online players:", err)
+	}
+	if status.TPS, err = readTPS(); err != nil {
+		fmt.Println("Error reading TPS:", err)
+	}
+	return status
+}
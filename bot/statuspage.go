@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hunterjsb/xn-mc/pkg/statuspage"
+)
+
+// statuspageClient returns a client for STATUSPAGE_PAGE_ID, or nil if Statuspage is not configured.
+func statuspageClient() *statuspage.Client {
+	apiKey, pageID := os.Getenv("STATUSPAGE_API_KEY"), os.Getenv("STATUSPAGE_PAGE_ID")
+	if apiKey == "" || pageID == "" {
+		return nil
+	}
+	return statuspage.NewClient(apiKey, pageID)
+}
+
+// setStatuspageComponent mirrors the server state onto STATUSPAGE_COMPONENT_ID.
+func setStatuspageComponent(status string) {
+	client := statuspageClient()
+	componentID := os.Getenv("STATUSPAGE_COMPONENT_ID")
+	if client == nil || componentID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := client.SetComponentStatus(ctx, componentID, status); err != nil {
+		fmt.Println("Error updating Statuspage component:", err)
+	}
+}
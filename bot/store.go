@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// readJSONFile decodes the file at path into v. A missing file leaves v untouched.
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile atomically writes v to path, keeping backups previous versions. User data
+// stores pass fileBackups(); stores that are rewritten often or can be rebuilt pass 0.
+func writeJSONFile(path string, v any, backups int) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644, backups)
+}
+
+// fileBackups is how many previous versions writeFileAtomic keeps (FILE_BACKUPS, default 3).
+func fileBackups() int {
+	if n, err := strconv.Atoi(os.Getenv("FILE_BACKUPS")); err == nil && n >= 0 {
+		return n
+	}
+	return 3
+}
+
+// writeFileAtomic replaces path with data so that a crash leaves either the old or the new
+// file, never a partial one. The data goes to a temporary file in the same directory, which
+// is synced and renamed over path. The previous versions are kept as path.1 (newest) to
+// path.<backups>.
+func writeFileAtomic(path string, data []byte, perm os.FileMode, backups int) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	// Harmless once the rename succeeded
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	if backups > 0 {
+		if err = rotateBackups(path, backups); err != nil {
+			return fmt.Errorf("backing up %s: %w", path, err)
+		}
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// rotateBackups shifts path.1..path.n-1 up by one and copies path to path.1.
+func rotateBackups(path string, n int) error {
+	current, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := n - 1; i >= 1; i-- {
+		err = os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.WriteFile(path+".1", current, 0644)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// sdNotify sends a state string to systemd when running as a Type=notify unit.
+// It is a no-op when NOTIFY_SOCKET is not set.
+func sdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// runWatchdog pings the systemd watchdog at half of WatchdogSec, but only while the
+// Discord gateway is still acknowledging heartbeats, so a hung bot gets restarted.
+func runWatchdog(s *discordgo.Session) {
+	usec, err := strconv.Atoi(os.Getenv("WATCHDOG_USEC"))
+	if err != nil || usec <= 0 {
+		return
+	}
+	timeout := time.Duration(usec) * time.Microsecond
+
+	ticker := time.NewTicker(timeout / 2)
+	for range ticker.C {
+		s.RLock()
+		lastAck := s.LastHeartbeatAck
+		s.RUnlock()
+
+		if time.Since(lastAck) > timeout {
+			fmt.Println("Discord heartbeat is stale, skipping watchdog ping")
+			continue
+		}
+		if err := sdNotify("WATCHDOG=1"); err != nil {
+			fmt.Println("Error pinging systemd watchdog:", err)
+		}
+	}
+}
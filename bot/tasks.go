@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// taskTimeouts are the default deadlines of long-running commands, overridden per command
+// with COMMAND_TIMEOUTS ("lagspots:5m,heapdump:20m").
+var taskTimeouts = map[string]time.Duration{
+	"lagspots": 2 * time.Minute,
+	"heapdump": 10 * time.Minute,
+	"restart":  15 * time.Minute,
+	"stop":     5 * time.Minute,
+	"prune":    30 * time.Minute,
+}
+
+// commandTimeouts are the COMMAND_TIMEOUTS overrides, parsed by loadConfig.
+var commandTimeouts = map[string]time.Duration{}
+
+// serverTasks take the server down, so at most one of them runs at a time.
+var serverTasks = map[string]bool{"restart": true, "stop": true}
+
+type task struct {
+	started time.Time
+	cancel  context.CancelFunc
+}
+
+var (
+	tasks   = map[string]*task{}
+	tasksMu sync.Mutex
+)
+
+func parseCommandTimeouts(raw string) map[string]time.Duration {
+	res := map[string]time.Duration{}
+	for name, value := range parseKeyValues("COMMAND_TIMEOUTS", raw) {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			fmt.Printf("Ignoring invalid COMMAND_TIMEOUTS entry for %s: %q\n", name, value)
+			continue
+		}
+		res[name] = timeout
+	}
+	return res
+}
+
+func taskTimeout(name string) time.Duration {
+	if timeout, ok := commandTimeouts[name]; ok {
+		return timeout
+	}
+	return taskTimeouts[name]
+}
+
+// startTask registers a running long task that `cancel <name>` can abort and gives it its deadline.
+// It fails if a task with the same name is already running. done must be called when it finishes.
+func startTask(name string) (ctx context.Context, done func(), err error) {
+	tasksMu.Lock()
+	defer tasksMu.Unlock()
+	for other := range tasks {
+		if other == name || serverTasks[name] && serverTasks[other] {
+			return nil, nil, fmt.Errorf("%s is already running, use `cancel %s` to abort it", other, other)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskTimeout(name))
+	t := &task{started: time.Now(), cancel: cancel}
+	tasks[name] = t
+	return ctx, func() {
+		cancel()
+		tasksMu.Lock()
+		if tasks[name] == t {
+			delete(tasks, name)
+		}
+		tasksMu.Unlock()
+	}, nil
+}
+
+// taskError describes why a task stopped early.
+func taskError(ctx context.Context, name string) string {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Sprintf("%s timed out after %s.", name, taskTimeout(name))
+	}
+	return name + " was cancelled."
+}
+
+// handleCancel aborts a running task, or lists them.
+func handleCancel(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	tasksMu.Lock()
+	defer tasksMu.Unlock()
+
+	if len(args) == 0 {
+		if len(tasks) == 0 {
+			s.ChannelMessageSend(m.ChannelID, "No tasks running.")
+			return
+		}
+		names := make([]string, 0, len(tasks))
+		for name := range tasks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var sb strings.Builder
+		sb.WriteString("TASKS:\n")
+		for _, name := range names {
+			fmt.Fprintf(&sb, "%s, started <t:%d:R>\n", name, tasks[name].started.Unix())
+		}
+		sb.WriteString("Use `cancel <task>` to abort one.")
+		s.ChannelMessageSend(m.ChannelID, sb.String())
+		return
+	}
+
+	t, ok := tasks[args[0]]
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, "No running task named "+args[0])
+		return
+	}
+	if serverTasks[args[0]] && !isAdmin(s, m) {
+		s.ChannelMessageSend(m.ChannelID, "Only admins can cancel a "+args[0]+".")
+		return
+	}
+	t.cancel()
+	s.ChannelMessageSend(m.ChannelID, "Cancelling "+args[0]+"...")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Theme re-skins the bot for other communities without code changes. It is read from
+// THEME_FILE (JSON), and any field left out keeps the default look.
+type Theme struct {
+	// Card and graph colors as "#rrggbb"
+	Background string
+	Text       string
+	Muted      string
+	Online     string
+	Offline    string
+
+	// Footer is drawn at the bottom of status cards
+	Footer string
+
+	StarEmoji  string
+	GraveEmoji string
+	RulesEmoji string
+
+	// TimestampStyle is the Discord timestamp format letter for "updated" times, R by default
+	TimestampStyle string
+}
+
+var theme = Theme{
+	StarEmoji:      "⭐",
+	GraveEmoji:     "🪦",
+	RulesEmoji:     "✅",
+	TimestampStyle: "R",
+}
+
+// loadTheme applies THEME_FILE on top of the defaults.
+func loadTheme() error {
+	path := os.Getenv("THEME_FILE")
+	if path == "" {
+		return nil
+	}
+	if err := readJSONFile(path, &theme); err != nil {
+		return err
+	}
+
+	for _, c := range []struct {
+		hex    string
+		target *color.RGBA
+	}{
+		{theme.Background, &cardBackground},
+		{theme.Text, &cardText},
+		{theme.Muted, &cardMuted},
+		{theme.Online, &cardGreen},
+		{theme.Offline, &cardRed},
+	} {
+		if c.hex == "" {
+			continue
+		}
+		parsed, err := parseHexColor(c.hex)
+		if err != nil {
+			return err
+		}
+		*c.target = parsed
+	}
+	return nil
+}
+
+func parseHexColor(hex string) (color.RGBA, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected #rrggbb", hex)
+	}
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 0xff}, nil
+}
+
+// timestamp formats t as a Discord timestamp in the theme's style.
+func timestamp(t time.Time) string {
+	return fmt.Sprintf("<t:%d:%s>", t.Unix(), theme.TimestampStyle)
+}
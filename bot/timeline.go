@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+	"github.com/hunterjsb/xn-mc/pkg/statuspage"
+)
+
+const (
+	recentLogSize    = 30
+	crashExcerptSize = 25
+	graphWindow      = 2 * time.Hour
+)
+
+// Outage is an unplanned server outage being documented in an admin thread.
+type Outage struct {
+	Start      time.Time
+	ThreadID   string
+	IncidentID string
+	Timeline   []string
+}
+
+var (
+	recentLog   []string
+	recentLogMu sync.Mutex
+
+	currentOutage *Outage
+)
+
+// recordLogLine keeps the last few log lines around for outage timelines.
+func recordLogLine(line string) {
+	recentLogMu.Lock()
+	defer recentLogMu.Unlock()
+
+	recentLog = append(recentLog, line)
+	if len(recentLog) > recentLogSize {
+		recentLog = recentLog[len(recentLog)-recentLogSize:]
+	}
+}
+
+func (o *Outage) add(s *discordgo.Session, entry string) {
+	o.Timeline = append(o.Timeline, fmt.Sprintf("%s %s", time.Now().Format("15:04:05"), entry))
+	s.ChannelMessageSend(o.ThreadID, entry)
+}
+
+// beginOutage opens an incident thread in the admin channel with everything known at the time of the crash.
+func beginOutage(s *discordgo.Session) {
+	outage := &Outage{Start: time.Now()}
+	go setStatuspageComponent(statuspage.StatusMajorOutage)
+
+	msg, err := s.ChannelMessageSend(adminChannel(), fmt.Sprintf("**OUTAGE** started <t:%d:f>", outage.Start.Unix()))
+	if err != nil {
+		fmt.Println("Error posting outage:", err)
+		return
+	}
+	thread, err := s.MessageThreadStart(adminChannel(), msg.ID, "Outage "+outage.Start.Format("2006-01-02 15:04"), 10080)
+	if err != nil {
+		fmt.Println("Error creating outage thread:", err)
+		return
+	}
+	outage.ThreadID = thread.ID
+	currentOutage = outage
+
+	outage.add(s, "Server process stopped unexpectedly.")
+
+	recentLogMu.Lock()
+	lastLines := strings.Join(recentLog, "\n")
+	recentLogMu.Unlock()
+	if lastLines != "" {
+		s.ChannelMessageSend(outage.ThreadID, "Last log lines:")
+		for _, block := range discordutil.CodeBlocks(lastLines, "") {
+			s.ChannelMessageSend(outage.ThreadID, block)
+		}
+	}
+
+	if name, excerpt, err := latestCrashReport(outage.Start.Add(-10 * time.Minute)); err == nil && excerpt != "" {
+		outage.add(s, "Crash report written: "+name)
+		for _, block := range discordutil.CodeBlocks(excerpt, "") {
+			s.ChannelMessageSend(outage.ThreadID, block)
+		}
+	}
+
+	if store, err := loadMetrics(); err == nil {
+		if graph, err := renderMetricsGraph(store.Samples, outage.Start.Add(-graphWindow)); err == nil {
+			s.ChannelFileSendWithMessage(outage.ThreadID, "TPS and players over the last two hours:", "metrics.png", bytes.NewReader(graph))
+		}
+	}
+
+	if client := statuspageClient(); client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		incident, err := client.CreateIncident(ctx, statuspage.IncidentParams{
+			Name:   "Minecraft server outage",
+			Status: statuspage.IncidentInvestigating,
+			Body:   "The Minecraft server went down unexpectedly. We are looking into it.",
+		})
+		if err != nil {
+			fmt.Println("Error creating outage incident:", err)
+		} else {
+			outage.IncidentID = incident.ID
+			outage.add(s, "Statuspage incident opened: "+incident.Shortlink)
+		}
+	}
+}
+
+// endOutage records the recovery and restart actions, and resolves the Statuspage incident with the timeline.
+func endOutage(s *discordgo.Session) {
+	outage := currentOutage
+	currentOutage = nil
+	if outage == nil {
+		return
+	}
+
+	restartsMu.Lock()
+	var restarts []Restart
+	readJSONFile(restartsFile(), &restarts)
+	restartsMu.Unlock()
+	for _, restart := range restarts {
+		if restart.Time.After(outage.Start) {
+			outage.add(s, fmt.Sprintf("%s by %s: %s", restart.Action, restart.Username, restart.Reason))
+		}
+	}
+	outage.add(s, fmt.Sprintf("Server is back up after %s.", time.Since(outage.Start).Round(time.Second)))
+
+	if client := statuspageClient(); client != nil && outage.IncidentID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		body := "The server is back up. Timeline:\n" + strings.Join(outage.Timeline, "\n")
+		if _, err := client.ResolveIncident(ctx, outage.IncidentID, body); err != nil {
+			fmt.Println("Error resolving outage incident:", err)
+		}
+	}
+
+	archived := true
+	s.ChannelEdit(outage.ThreadID, &discordgo.ChannelEdit{Archived: &archived})
+}
+
+// latestCrashReport returns the start of the newest crash report written after since.
+func latestCrashReport(since time.Time) (name string, excerpt string, err error) {
+	reports, err := filepath.Glob("../server/crash-reports/*.txt")
+	if err != nil || len(reports) == 0 {
+		return "", "", err
+	}
+	sort.Strings(reports) // crash-YYYY-MM-DD_HH.MM.SS-server.txt sorts by time
+
+	latest := reports[len(reports)-1]
+	info, err := os.Stat(latest)
+	if err != nil || info.ModTime().Before(since) {
+		return "", "", err
+	}
+	data, err := os.ReadFile(latest)
+	if err != nil {
+		return "", "", err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > crashExcerptSize {
+		lines = lines[:crashExcerptSize]
+	}
+	return filepath.Base(latest), strings.Join(lines, "\n"), nil
+}
+
+// renderMetricsGraph plots TPS (green) and online players (blue) for samples after since.
+func renderMetricsGraph(samples []MetricsSample, since time.Time) ([]byte, error) {
+	const width, height, margin = 480, 200, 24
+
+	var window []MetricsSample
+	maxPlayers := 1
+	for _, sample := range samples {
+		if sample.Time.After(since) {
+			window = append(window, sample)
+			maxPlayers = max(maxPlayers, len(sample.Players))
+		}
+	}
+	if len(window) < 2 {
+		return nil, fmt.Errorf("not enough samples to graph")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{cardBackground}, image.Point{}, draw.Src)
+	drawText(img, margin, 6, "TPS", 1, cardGreen)
+	drawText(img, margin+30, 6, "PLAYERS", 1, color.RGBA{0x58, 0x65, 0xf2, 0xff})
+
+	plot := func(value func(MetricsSample) float64, c color.Color) {
+		span := window[len(window)-1].Time.Sub(window[0].Time).Seconds()
+		for i := 1; i < len(window); i++ {
+			x0 := margin + int(window[i-1].Time.Sub(window[0].Time).Seconds()/span*(width-2*margin))
+			x1 := margin + int(window[i].Time.Sub(window[0].Time).Seconds()/span*(width-2*margin))
+			y0 := height - margin - int(value(window[i-1])*(height-2*margin))
+			y1 := height - margin - int(value(window[i])*(height-2*margin))
+			drawLine(img, x0, y0, x1, y1, c)
+		}
+	}
+	plot(func(sample MetricsSample) float64 { return min(sample.TPS, 20) / 20 }, cardGreen)
+	plot(func(sample MetricsSample) float64 { return float64(len(sample.Players)) / float64(maxPlayers) }, color.RGBA{0x58, 0x65, 0xf2, 0xff})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	steps := max(abs(x1-x0), abs(y1-y0), 1)
+	for i := 0; i <= steps; i++ {
+		img.Set(x0+(x1-x0)*i/steps, y0+(y1-y0)*i/steps, c)
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
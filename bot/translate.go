@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Translator translates text into target, reporting the detected source language.
+type Translator interface {
+	Translate(ctx context.Context, text string, target string) (translated string, source string, err error)
+}
+
+// translator is set when TRANSLATE_PROVIDER is configured.
+var translator Translator
+
+func newTranslator() (Translator, error) {
+	switch provider := os.Getenv("TRANSLATE_PROVIDER"); provider {
+	case "libretranslate":
+		return &libreTranslate{baseURL: os.Getenv("TRANSLATE_URL"), apiKey: os.Getenv("TRANSLATE_API_KEY")}, nil
+	case "deepl":
+		return &deepL{apiKey: os.Getenv("TRANSLATE_API_KEY")}, nil
+	default:
+		return nil, fmt.Errorf("unsupported TRANSLATE_PROVIDER %q", provider)
+	}
+}
+
+// translateTarget is the language chat is translated into (TRANSLATE_TARGET, default en).
+func translateTarget() string {
+	if target := os.Getenv("TRANSLATE_TARGET"); target != "" {
+		return target
+	}
+	return "en"
+}
+
+type libreTranslate struct {
+	baseURL string
+	apiKey  string
+}
+
+func (l *libreTranslate) Translate(ctx context.Context, text string, target string) (string, string, error) {
+	body, err := json.Marshal(map[string]string{
+		"q":       text,
+		"source":  "auto",
+		"target":  target,
+		"api_key": l.apiKey,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(l.baseURL, "/")+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		TranslatedText   string `json:"translatedText"`
+		DetectedLanguage struct {
+			Language string `json:"language"`
+		} `json:"detectedLanguage"`
+		Error string `json:"error"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("libretranslate: %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("libretranslate: %s %s", resp.Status, result.Error)
+	}
+	return result.TranslatedText, result.DetectedLanguage.Language, nil
+}
+
+type deepL struct {
+	apiKey string
+}
+
+func (d *deepL) Translate(ctx context.Context, text string, target string) (string, string, error) {
+	// Free plan keys end in ":fx" and use a separate host
+	endpoint := "https://api.deepl.com/v2/translate"
+	if strings.HasSuffix(d.apiKey, ":fx") {
+		endpoint = "https://api-free.deepl.com/v2/translate"
+	}
+	form := url.Values{"text": {text}, "target_lang": {strings.ToUpper(target)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+d.apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("deepl: %s", resp.Status)
+	}
+
+	var result struct {
+		Translations []struct {
+			Text   string `json:"text"`
+			Source string `json:"detected_source_language"`
+		} `json:"translations"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.Translations) == 0 {
+		return "", "", fmt.Errorf("deepl: unexpected response")
+	}
+	return result.Translations[0].Text, strings.ToLower(result.Translations[0].Source), nil
+}
+
+// translateInline returns an italic translation line for text, or "" if it is already in the target language.
+func translateInline(text string) string {
+	if translator == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	translated, source, err := translator.Translate(ctx, text, translateTarget())
+	if err != nil {
+		fmt.Println("Error translating chat:", err)
+		return ""
+	}
+	if strings.EqualFold(source, translateTarget()) || translated == text {
+		return ""
+	}
+	return fmt.Sprintf("\n> *(%s) %s*", source, translated)
+}
+
+// handleTranslate translates the message the command replies to.
+func handleTranslate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if translator == nil {
+		s.ChannelMessageSend(m.ChannelID, "Translation is not configured.")
+		return
+	}
+	if m.MessageReference == nil {
+		s.ChannelMessageSend(m.ChannelID, "Reply to a message with `translate` to translate it.")
+		return
+	}
+	original, err := s.ChannelMessage(m.MessageReference.ChannelID, m.MessageReference.MessageID)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to fetch message: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	translated, source, err := translator.Translate(ctx, original.Content, translateTarget())
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "Failed to translate: "+err.Error())
+		return
+	}
+	s.ChannelMessageSendReply(m.ChannelID, fmt.Sprintf("(%s) %s", source, translated), m.Reference())
+}
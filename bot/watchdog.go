@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/monitor"
+)
+
+const expectedStopWindow = 5 * time.Minute
+
+var (
+	expectedStopUntil time.Time
+	expectedStopMu    sync.Mutex
+)
+
+// markExpectedStop tells the watchdog that the server is about to go down on purpose.
+func markExpectedStop() {
+	expectedStopMu.Lock()
+	defer expectedStopMu.Unlock()
+	expectedStopUntil = time.Now().Add(expectedStopWindow)
+}
+
+func stopExpected() bool {
+	expectedStopMu.Lock()
+	defer expectedStopMu.Unlock()
+	return time.Now().Before(expectedStopUntil)
+}
+
+// watchForOutages reports the server process disappearing without anyone stopping it.
+func watchForOutages(s *discordgo.Session) {
+	monitor.Watch(context.Background(), monitor.Options{
+		Running: serverRunning,
+		OnDown: func() {
+			if stopExpected() {
+				return
+			}
+			sendAlert(s, channelID, "**OUTAGE**: the Minecraft server stopped unexpectedly.")
+			notifySubscribers(s, "The Minecraft server went down unexpectedly. Staff have been alerted.")
+			beginOutage(s)
+		},
+		OnUp: func() {
+			if currentOutage != nil {
+				endOutage(s)
+			}
+		},
+	})
+}
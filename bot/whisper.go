@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// whisperReplyPrefix is what players start a chat message with to answer staff.
+const whisperReplyPrefix = "!reply "
+
+var playerNameRegex = regexp.MustCompile(`^\w{3,16}$`)
+
+var (
+	// whisperReplies maps a lowercase player name to the channel their replies go to,
+	// the one the latest whisper to them came from
+	whisperReplies   = map[string]string{}
+	whisperRepliesMu sync.Mutex
+)
+
+// handleMsg whispers to an online player from a command channel: `msg <player> <text>`. Like
+// the console commands it replaces, anyone who can use the channel can send one.
+func handleMsg(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `msg <player> <text>`")
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, whisper(m, args[0], strings.Join(args[1:], " ")))
+}
+
+// handleWhisperDM whispers to an online player from a staff member's DM: `@player text`.
+func handleWhisperDM(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isStaff(s, m.Author.ID) {
+		return
+	}
+	player, text, found := strings.Cut(strings.TrimPrefix(m.Content, "@"), " ")
+	if !found || strings.TrimSpace(text) == "" {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `@player message`")
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, whisper(m, player, strings.TrimSpace(text)))
+}
+
+// whisper tells player text in game and routes their replies back to m's channel. It
+// returns the confirmation or error to show the sender.
+func whisper(m *discordgo.MessageCreate, player string, text string) string {
+	if !playerNameRegex.MatchString(player) {
+		return "Invalid player name."
+	}
+	players, _, err := onlinePlayers()
+	if err != nil {
+		return "**ERROR**: " + err.Error()
+	}
+	online := false
+	for _, name := range players {
+		if strings.EqualFold(name, player) {
+			player, online = name, true
+		}
+	}
+	if !online {
+		return player + " is not online."
+	}
+
+	name := m.Author.Username
+	if m.Member != nil && m.Member.Nick != "" {
+		name = m.Member.Nick
+	}
+	message, err := json.Marshal([]map[string]any{
+		{"text": "[Discord] ", "color": "blue"},
+		{"text": name + " whispers: ", "color": "gray", "italic": true},
+		{"text": text, "color": "white"},
+		{"text": " [reply]", "color": "dark_aqua", "clickEvent": map[string]string{"action": "suggest_command", "value": whisperReplyPrefix}},
+	})
+	if err != nil {
+		return "**ERROR**: " + err.Error()
+	}
+	if _, err = rconExecute(fmt.Sprintf("tellraw %s %s", player, message)); err != nil {
+		return "**ERROR**: " + err.Error()
+	}
+
+	whisperRepliesMu.Lock()
+	whisperReplies[strings.ToLower(player)] = m.ChannelID
+	whisperRepliesMu.Unlock()
+	return fmt.Sprintf("Sent to %s. They can answer in chat with `%s<message>`.", player, whisperReplyPrefix)
+}
+
+// relayWhisperReply posts a player's `!reply` chat line back to whoever whispered to them
+// last. It reports whether line was a reply, so it is kept out of the chat relays.
+func relayWhisperReply(s *discordgo.Session, line string) bool {
+	player, message, ok := parseChatLine(line)
+	if !ok || !strings.HasPrefix(message, whisperReplyPrefix) {
+		return false
+	}
+
+	whisperRepliesMu.Lock()
+	replyChannelID, ok := whisperReplies[strings.ToLower(player)]
+	whisperRepliesMu.Unlock()
+	if !ok {
+		rconExecute(fmt.Sprintf("tellraw %s {\"text\":\"No one on Discord has messaged you.\",\"color\":\"gray\"}", player))
+		return true
+	}
+	s.ChannelMessageSendComplex(replyChannelID, &discordgo.MessageSend{
+		Content:         fmt.Sprintf("**%s** replied: %s", player, strings.TrimPrefix(message, whisperReplyPrefix)),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	return true
+}
+
+// isStaff reports whether userID is an admin in the main channel's guild, for messages that
+// arrive outside of it such as DMs.
+func isStaff(s *discordgo.Session, userID string) bool {
+	channel, err := s.State.Channel(channelID)
+	if err != nil {
+		if channel, err = s.Channel(channelID); err != nil {
+			return false
+		}
+	}
+	if roleID := os.Getenv("ADMIN_ROLE_ID"); roleID != "" {
+		if member, err := guildMember(s, channel.GuildID, userID); err == nil && hasRole(member, roleID) {
+			return true
+		}
+	}
+	perms, err := s.UserChannelPermissions(userID, channelID)
+	return err == nil && perms&discordgo.PermissionAdministrator != 0
+}
@@ -0,0 +1,31 @@
+package main
+
+import "fmt"
+
+const lineWorkerQueue = 256
+
+// lineWorker handles log lines in its own goroutine, one at a time and in order, so handlers
+// that call Discord, rcon or other services can't hold up the log relay.
+type lineWorker struct {
+	name  string
+	lines chan string
+}
+
+func startLineWorker(name string, handle func(line string)) *lineWorker {
+	w := &lineWorker{name: name, lines: make(chan string, lineWorkerQueue)}
+	go func() {
+		for line := range w.lines {
+			handle(line)
+		}
+	}()
+	return w
+}
+
+// send queues line, dropping it if the worker has fallen too far behind.
+func (w *lineWorker) send(line string) {
+	select {
+	case w.lines <- line:
+	default:
+		fmt.Printf("Dropping log line, %s worker is %d lines behind\n", w.name, lineWorkerQueue)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// followFile sends the lines appended to path until ctx is done, starting at its current end.
+// When the file is replaced or truncated, as latest.log is on every server start, it starts
+// over from the beginning of the new file.
+func followFile(ctx context.Context, path string) <-chan string {
+	lines := make(chan string, 256)
+	go func() {
+		defer close(lines)
+
+		var last os.FileInfo
+		var offset int64
+		if info, err := os.Stat(path); err == nil {
+			last, offset = info, info.Size()
+		}
+
+		var partial string
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if last != nil && (!os.SameFile(last, info) || info.Size() < offset) {
+				offset, partial = 0, ""
+			}
+			last = info
+			if info.Size() == offset {
+				continue
+			}
+
+			data, err := readFrom(path, offset)
+			if err != nil {
+				fmt.Println("Error reading server log:", err)
+				continue
+			}
+			offset += int64(len(data))
+
+			parts := strings.Split(partial+string(data), "\n")
+			partial = parts[len(parts)-1]
+			for _, line := range parts[:len(parts)-1] {
+				select {
+				case lines <- strings.TrimSuffix(line, "\r"):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return lines
+}
+
+func readFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
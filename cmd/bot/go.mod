@@ -0,0 +1,19 @@
+module github.com/hunterjsb/xn-mc/cmd/bot
+
+go 1.21.3
+
+require (
+	github.com/bwmarrin/discordgo v0.27.1
+	github.com/hunterjsb/xn-mc/pkg/mcbot v0.0.0
+)
+
+require (
+	github.com/gorilla/websocket v1.4.2 // indirect
+	github.com/hunterjsb/xn-mc/pkg/discordutil v0.0.0 // indirect
+	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b // indirect
+	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68 // indirect
+)
+
+replace github.com/hunterjsb/xn-mc/pkg/mcbot => ../../pkg/mcbot
+
+replace github.com/hunterjsb/xn-mc/pkg/discordutil => ../../pkg/discordutil
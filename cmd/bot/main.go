@@ -0,0 +1,156 @@
+// Command bot is a ready-made Discord bot for any Minecraft server, assembled from the mcbot
+// packages: it relays the server log, alerts when the server goes down or comes back, and
+// answers !status and !help. With a hosting backend it also has the xn-mc server commands:
+// !start, !stop, !restart and console commands. The xn-mc bot in bot/ is built from the
+// same packages.
+//
+//	DISCORD_TOKEN=... LOG_CHANNEL_ID=... bot
+//
+// DISCORD_TOKEN and LOG_CHANNEL_ID must be set. Optional settings:
+//
+//	CONSOLE_CHANNEL_ID  relay the log into one thread per server run in this channel
+//	COMMAND_PREFIX      command prefix (default !)
+//	MC_ADDRESS          server to ping (default localhost:25565)
+//	LOG_FILE            server log to follow (default logs/latest.log)
+//	SERVER_BACKEND      pterodactyl or docker, to control the server and relay its console
+//
+// The pterodactyl backend reads PTERODACTYL_URL, PTERODACTYL_API_KEY and
+// PTERODACTYL_SERVER_ID, and the docker backend DOCKER_HOST and DOCKER_CONTAINER. With a
+// backend, commands are only answered in ADMIN_CHANNEL_ID, since anyone who can use them
+// controls the server.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/commands"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/docker"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/integrations/pterodactyl"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/monitor"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/relay"
+)
+
+func main() {
+	token, logChannelID := os.Getenv("DISCORD_TOKEN"), os.Getenv("LOG_CHANNEL_ID")
+	if token == "" || logChannelID == "" {
+		fail("DISCORD_TOKEN and LOG_CHANNEL_ID must be set")
+	}
+	address := getenv("MC_ADDRESS", "localhost:25565")
+	prefix := getenv("COMMAND_PREFIX", "!")[0]
+
+	server, err := newServer(os.Getenv("SERVER_BACKEND"))
+	if err != nil {
+		fail("Error configuring server backend: " + err.Error())
+	}
+
+	router := mcbot.NewRouter(prefix)
+	if server != nil {
+		adminChannelID := os.Getenv("ADMIN_CHANNEL_ID")
+		if adminChannelID == "" {
+			fail("ADMIN_CHANNEL_ID must be set with SERVER_BACKEND")
+		}
+		router.Allow = func(m *discordgo.MessageCreate) bool { return m.ChannelID == adminChannelID }
+		commands.Register(router, commands.Options{
+			Server:        server,
+			Console:       func(cmd string) (string, error) { return server.SendCommand(context.Background(), cmd) },
+			RequireReason: true,
+		})
+	}
+	router.Handle(func(c *mcbot.Context) {
+		c.Reply(formatStatus(address))
+	}, "status")
+	router.Handle(func(c *mcbot.Context) {
+		c.Reply("Commands: " + strings.Join(router.Commands(), ", "))
+	}, "help")
+
+	bot, err := mcbot.New(mcbot.Options{
+		Token:   token,
+		Intents: discordgo.IntentsGuildMessages | discordgo.IntentMessageContent,
+		Router:  router,
+	})
+	if err != nil {
+		fail("Error creating bot: " + err.Error())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	bot.Go(func(s *discordgo.Session) {
+		logRelay := relay.New(s, relay.Options{
+			ChannelID:        logChannelID,
+			ConsoleChannelID: os.Getenv("CONSOLE_CHANNEL_ID"),
+		})
+		if server == nil {
+			logRelay.Run(ctx, followFile(ctx, getenv("LOG_FILE", "logs/latest.log")), nil)
+			return
+		}
+		lines, err := server.Logs(ctx)
+		if err != nil {
+			fmt.Println("Error reading server console:", err)
+			return
+		}
+		logRelay.Run(ctx, lines, nil)
+	})
+	bot.Go(func(s *discordgo.Session) {
+		var running func() bool
+		if server != nil {
+			running = func() bool { return mcbot.Running(ctx, server) }
+		}
+		monitor.Watch(ctx, monitor.Options{
+			Running: running,
+			Address: address,
+			OnDown:  func() { s.ChannelMessageSend(logChannelID, "**OUTAGE**: the Minecraft server is not responding.") },
+			OnUp:    func() { s.ChannelMessageSend(logChannelID, "The Minecraft server is back up.") },
+		})
+	})
+
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	if err = bot.Run(ctx); err != nil {
+		fail("Error running bot: " + err.Error())
+	}
+}
+
+func formatStatus(address string) string {
+	status, err := monitor.Ping(address)
+	if err != nil {
+		return "The Minecraft server is offline."
+	}
+	return fmt.Sprintf("Online: %d/%d players, version %s\n%s", status.Players.Online, status.Players.Max, status.Version.Name, status.MOTD())
+}
+
+// newServer creates the hosting backend named by backend, or nil if none is configured.
+func newServer(backend string) (mcbot.Server, error) {
+	switch backend {
+	case "":
+		return nil, nil
+	case "pterodactyl":
+		return pterodactyl.New(pterodactyl.Options{
+			URL:      os.Getenv("PTERODACTYL_URL"),
+			APIKey:   os.Getenv("PTERODACTYL_API_KEY"),
+			ServerID: os.Getenv("PTERODACTYL_SERVER_ID"),
+		})
+	case "docker":
+		return docker.New(docker.Options{Host: os.Getenv("DOCKER_HOST"), Container: os.Getenv("DOCKER_CONTAINER")})
+	default:
+		return nil, fmt.Errorf("unknown SERVER_BACKEND %q", backend)
+	}
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func fail(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
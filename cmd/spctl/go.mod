@@ -0,0 +1,7 @@
+module github.com/hunterjsb/xn-mc/cmd/spctl
+
+go 1.21.3
+
+require github.com/hunterjsb/xn-mc/pkg/statuspage v0.0.0
+
+replace github.com/hunterjsb/xn-mc/pkg/statuspage => ../../pkg/statuspage
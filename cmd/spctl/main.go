@@ -0,0 +1,197 @@
+// Command spctl manages the xn-mc Statuspage from the command line or cron, for when the bot is down.
+//
+//	spctl components
+//	spctl set <component-id> <status>
+//	spctl incidents [-unresolved]
+//	spctl incident create -name <name> [-status investigating] [-body <text>] [-components id,id] [-component-status major_outage]
+//	spctl incident update <incident-id> -status <status> [-body <text>]
+//	spctl incident resolve <incident-id> [-body <text>]
+//	spctl metric push <metric-id> <value> [-time <unix seconds>]
+//
+// STATUSPAGE_API_KEY and STATUSPAGE_PAGE_ID must be set.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hunterjsb/xn-mc/pkg/statuspage"
+)
+
+func main() {
+	apiKey, pageID := os.Getenv("STATUSPAGE_API_KEY"), os.Getenv("STATUSPAGE_PAGE_ID")
+	if apiKey == "" || pageID == "" {
+		fail("STATUSPAGE_API_KEY and STATUSPAGE_PAGE_ID must be set")
+	}
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	client := statuspage.NewClient(apiKey, pageID)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var err error
+	switch args := os.Args[2:]; os.Args[1] {
+	case "components":
+		err = listComponents(ctx, client)
+	case "set":
+		err = setComponent(ctx, client, args)
+	case "incidents":
+		err = listIncidents(ctx, client, args)
+	case "incident":
+		err = incident(ctx, client, args)
+	case "metric":
+		err = metric(ctx, client, args)
+	default:
+		usage()
+	}
+	if err != nil {
+		fail(err.Error())
+	}
+}
+
+func usage() {
+	fail("usage: spctl components | set <component-id> <status> | incidents [-unresolved] | incident create|update|resolve ... | metric push <metric-id> <value>")
+}
+
+func fail(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
+
+func listComponents(ctx context.Context, client *statuspage.Client) error {
+	components, err := client.ListComponents(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tGROUP")
+	for _, component := range components {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", component.ID, component.Name, component.Status, component.GroupID)
+	}
+	return w.Flush()
+}
+
+func setComponent(ctx context.Context, client *statuspage.Client, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: spctl set <component-id> <status>")
+	}
+	component, err := client.SetComponentStatus(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is now %s\n", component.Name, component.Status)
+	return nil
+}
+
+func listIncidents(ctx context.Context, client *statuspage.Client, args []string) error {
+	fs := flag.NewFlagSet("incidents", flag.ExitOnError)
+	unresolved := fs.Bool("unresolved", false, "only list unresolved incidents")
+	fs.Parse(args)
+
+	var incidents []statuspage.Incident
+	var err error
+	if *unresolved {
+		incidents, err = client.ListUnresolvedIncidents(ctx)
+	} else {
+		incidents, err = client.ListIncidents(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tIMPACT\tCREATED")
+	for _, incident := range incidents {
+		created := ""
+		if incident.CreatedAt != nil {
+			created = incident.CreatedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", incident.ID, incident.Name, incident.Status, incident.Impact, created)
+	}
+	return w.Flush()
+}
+
+func incident(ctx context.Context, client *statuspage.Client, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: spctl incident create|update|resolve ...")
+	}
+
+	fs := flag.NewFlagSet("incident "+args[0], flag.ExitOnError)
+	name := fs.String("name", "", "incident name")
+	status := fs.String("status", "", "incident status")
+	body := fs.String("body", "", "update message")
+	components := fs.String("components", "", "comma separated IDs of affected components")
+	componentStatus := fs.String("component-status", statuspage.StatusMajorOutage, "status to set affected components to")
+
+	var res statuspage.Incident
+	var err error
+	switch args[0] {
+	case "create":
+		fs.Parse(args[1:])
+		if *name == "" {
+			return fmt.Errorf("-name is required")
+		}
+		params := statuspage.IncidentParams{Name: *name, Status: *status, Body: *body}
+		if params.Status == "" {
+			params.Status = statuspage.IncidentInvestigating
+		}
+		if *components != "" {
+			params.ComponentIDs = strings.Split(*components, ",")
+			params.Components = map[string]string{}
+			for _, id := range params.ComponentIDs {
+				params.Components[id] = *componentStatus
+			}
+		}
+		res, err = client.CreateIncident(ctx, params)
+	case "update":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: spctl incident update <incident-id> -status <status> [-body <text>]")
+		}
+		fs.Parse(args[2:])
+		res, err = client.UpdateIncident(ctx, args[1], statuspage.IncidentParams{Status: *status, Body: *body})
+	case "resolve":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: spctl incident resolve <incident-id> [-body <text>]")
+		}
+		fs.Parse(args[2:])
+		res, err = client.ResolveIncident(ctx, args[1], *body)
+	default:
+		return fmt.Errorf("unknown incident command %q", args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s (%s) %s\n", res.ID, res.Name, res.Status, res.Shortlink)
+	return nil
+}
+
+func metric(ctx context.Context, client *statuspage.Client, args []string) error {
+	if len(args) < 3 || args[0] != "push" {
+		return fmt.Errorf("usage: spctl metric push <metric-id> <value> [-time <unix seconds>]")
+	}
+
+	value, err := strconv.ParseFloat(args[2], 64)
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %w", args[2], err)
+	}
+
+	fs := flag.NewFlagSet("metric push", flag.ExitOnError)
+	at := fs.Int64("time", time.Now().Unix(), "timestamp of the data point in unix seconds")
+	fs.Parse(args[3:])
+
+	if err = client.AddMetricDataPoint(ctx, args[1], time.Unix(*at, 0), value); err != nil {
+		return err
+	}
+	fmt.Printf("Pushed %v to %s\n", value, args[1])
+	return nil
+}
@@ -0,0 +1,3 @@
+module github.com/hunterjsb/xn-mc/pkg/discordutil
+
+go 1.21.3
@@ -0,0 +1,47 @@
+// Package discordutil holds helpers for producing Discord output that are shared by the
+// bot and other xn-mc tools.
+package discordutil
+
+import "strings"
+
+// MaxMessageLength is the maximum number of characters Discord accepts in a message.
+const MaxMessageLength = 2000
+
+// SplitMessage splits text into chunks of at most limit characters, breaking on newlines
+// where possible. Lines longer than limit are hard-wrapped.
+func SplitMessage(text string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for len([]rune(line)) > limit {
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			runes := []rune(line)
+			chunks = append(chunks, string(runes[:limit]))
+			line = string(runes[limit:])
+		}
+		if len([]rune(current.String()))+len([]rune(line)) > limit {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// CodeBlocks splits text into messages that each fit in a ``` code block of the given language.
+func CodeBlocks(text string, language string) []string {
+	fence := "```" + language + "\n"
+	limit := MaxMessageLength - len(fence) - len("\n```")
+
+	var blocks []string
+	for _, chunk := range SplitMessage(strings.TrimRight(text, "\n"), limit) {
+		blocks = append(blocks, fence+strings.TrimRight(chunk, "\n")+"\n```")
+	}
+	return blocks
+}
@@ -0,0 +1,71 @@
+package discordutil
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{"short", "hello", 10, []string{"hello"}},
+		{"exact limit", "0123456789", 10, []string{"0123456789"}},
+		{"one over limit", "0123456789a", 10, []string{"0123456789", "a"}},
+		{"breaks on newlines", "abc\ndef\nghi", 8, []string{"abc\ndef\n", "ghi"}},
+		{"long line is hard-wrapped", "ab\n" + strings.Repeat("x", 25), 10, []string{"ab\n", "xxxxxxxxxx", "xxxxxxxxxx", "xxxxx"}},
+		{"counts runes not bytes", "ééééé", 5, []string{"ééééé"}},
+		{"empty", "", 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitMessage(tt.text, tt.limit)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("SplitMessage(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			if strings.Join(got, "") != tt.text {
+				t.Errorf("chunks %q do not join back to the input", got)
+			}
+		})
+	}
+}
+
+func TestSplitMessageMaxLength(t *testing.T) {
+	exact := strings.Repeat("a", MaxMessageLength)
+	if got := SplitMessage(exact, MaxMessageLength); len(got) != 1 {
+		t.Errorf("%d characters split into %d chunks, want 1", MaxMessageLength, len(got))
+	}
+	if got := SplitMessage(exact+"b", MaxMessageLength); len(got) != 2 || got[1] != "b" {
+		t.Errorf("%d characters split into %q, want the last character on its own", MaxMessageLength+1, got)
+	}
+}
+
+func TestCodeBlocks(t *testing.T) {
+	lines := []string{strings.Repeat("y", 3*MaxMessageLength)}
+	for i := 0; i < 500; i++ {
+		lines = append(lines, "line of log output")
+	}
+	text := strings.Join(lines, "\n")
+
+	for _, language := range []string{"", "ansi", "javascript"} {
+		blocks := CodeBlocks(text, language)
+		var contents strings.Builder
+		for _, block := range blocks {
+			if n := utf8.RuneCountInString(block); n > MaxMessageLength {
+				t.Fatalf("language %q: block of %d characters exceeds %d", language, n, MaxMessageLength)
+			}
+			fence := "```" + language + "\n"
+			if !strings.HasPrefix(block, fence) || !strings.HasSuffix(block, "\n```") {
+				t.Fatalf("language %q: block is not fenced: %.40q", language, block)
+			}
+			contents.WriteString(strings.TrimSuffix(strings.TrimPrefix(block, fence), "\n```"))
+		}
+		if got, want := contents.String(), strings.ReplaceAll(text, "\n", ""); strings.ReplaceAll(got, "\n", "") != want {
+			t.Errorf("language %q: blocks lost content", language)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package mcbot is the Discord side of the xn-mc bot that other Minecraft communities can
+// build on: a prefix command router, a session lifecycle and the Server interface hosting
+// backends implement. The relay and monitor packages add the console log relay and the
+// outage watcher, commands the server commands, and the integrations packages the
+// Pterodactyl and Docker backends, DNS checks and moderation. cmd/bot puts them together
+// into a ready-made bot.
+package mcbot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Options configures a Bot.
+type Options struct {
+	Token string
+
+	// Intents are the gateway events to receive. Message content is needed for prefix commands.
+	Intents discordgo.Intent
+
+	// Router, if set, handles prefix commands
+	Router *Router
+}
+
+// Bot is a Discord session plus the background tasks that run while it is connected.
+type Bot struct {
+	Session *discordgo.Session
+
+	tasks []func(s *discordgo.Session)
+}
+
+// New creates the session without connecting. Add handlers to Session and tasks with Go
+// before calling Run.
+func New(opts Options) (*Bot, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("mcbot: no token")
+	}
+	session, err := discordgo.New("Bot " + opts.Token)
+	if err != nil {
+		return nil, err
+	}
+	session.Identify.Intents = opts.Intents
+	if opts.Router != nil {
+		session.AddHandler(opts.Router.MessageCreate)
+	}
+	return &Bot{Session: session}, nil
+}
+
+// Go registers task to be started in its own goroutine once the session is open.
+func (b *Bot) Go(task func(s *discordgo.Session)) {
+	b.tasks = append(b.tasks, task)
+}
+
+// Run connects, starts the tasks and blocks until ctx is done, then closes the session.
+func (b *Bot) Run(ctx context.Context) error {
+	if err := b.Session.Open(); err != nil {
+		return err
+	}
+	defer b.Session.Close()
+
+	for _, task := range b.tasks {
+		go task(b.Session)
+	}
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package commands is the server command set of the xn-mc bot: status, start, stop and
+// restart through an mcbot.Server, with anything else run on the server console. Bots add
+// their own commands to the same router.
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot/relay"
+)
+
+// Options configures the server commands.
+type Options struct {
+	Server mcbot.Server
+
+	// Console, if set, runs commands nothing else handles and replies with their output
+	Console func(cmd string) (string, error)
+
+	// Begin, if set, runs before stop and restart, e.g. to check permissions or record who
+	// asked. It returns the context for the action and a function called once it is
+	// finished, or an error that is replied instead.
+	Begin func(c *mcbot.Context, action string, reason string) (context.Context, func(), error)
+
+	// RequireReason makes stop and restart take a reason, as in `stop <reason>`
+	RequireReason bool
+
+	// StartTimeout is how long restart waits for the server to come back (default 1 minute)
+	StartTimeout time.Duration
+}
+
+// Register adds status, start, stop and restart to r, and the console fallback if set.
+func Register(r *mcbot.Router, opts Options) {
+	r.Handle(func(c *mcbot.Context) {
+		if mcbot.Running(context.Background(), opts.Server) {
+			c.Reply("Minecraft server is running.")
+		} else {
+			c.Reply("Minecraft server is not running.")
+		}
+	}, "status")
+	r.Handle(func(c *mcbot.Context) {
+		if err := opts.Server.Start(context.Background()); err != nil {
+			c.Reply("Failed to start the Minecraft server: " + err.Error())
+			return
+		}
+		c.Reply("Minecraft server started.")
+	}, "start")
+	r.Handle(func(c *mcbot.Context) {
+		ctx, done, ok := begin(c, opts)
+		if !ok {
+			return
+		}
+		defer done()
+
+		if err := opts.Server.Stop(ctx); err != nil {
+			c.Reply("Failed to stop the Minecraft server: " + err.Error())
+			return
+		}
+		c.Reply("Minecraft server stopped.")
+	}, "stop")
+	r.Handle(func(c *mcbot.Context) {
+		ctx, done, ok := begin(c, opts)
+		if !ok {
+			return
+		}
+		defer done()
+
+		Restart(ctx, c.Session, c.Message.ChannelID, opts)
+	}, "restart")
+
+	if opts.Console != nil {
+		r.NotFound = func(c *mcbot.Context) { runConsole(c, opts.Console) }
+	}
+}
+
+// begin checks the reason and runs opts.Begin for the stop or restart in c.
+func begin(c *mcbot.Context, opts Options) (context.Context, func(), bool) {
+	reason := strings.Join(c.Args, " ")
+	if opts.RequireReason && reason == "" {
+		c.Reply(fmt.Sprintf("Usage: `%s <reason>`", c.Name))
+		return nil, nil, false
+	}
+	if opts.Begin == nil {
+		return context.Background(), func() {}, true
+	}
+	ctx, done, err := opts.Begin(c, c.Name, reason)
+	if err != nil {
+		c.Reply(err.Error())
+		return nil, nil, false
+	}
+	return ctx, done, true
+}
+
+// Restart restarts opts.Server and waits for it to be up, showing progress in channelID as
+// one message edited as each step completes.
+func Restart(ctx context.Context, s *discordgo.Session, channelID string, opts Options) error {
+	progress := StartProgress(s, channelID, "RESTART", "Restarting the server", "Waiting for it to come back")
+	if err := opts.Server.Restart(ctx); err != nil {
+		return progress.Fail(err)
+	}
+	progress.Next()
+
+	timeout := opts.StartTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	if err := waitForStart(ctx, opts.Server, timeout); err != nil {
+		return progress.Fail(err)
+	}
+	progress.Done("Minecraft server restarted.")
+	return nil
+}
+
+func waitForStart(ctx context.Context, server mcbot.Server, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if mcbot.Running(ctx, server) {
+			return nil
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("server did not start within %s", timeout)
+}
+
+// runConsole runs the command in c and replies with its output, coloured if the server
+// formatted it.
+func runConsole(c *mcbot.Context, console func(cmd string) (string, error)) {
+	response, err := console(c.Raw)
+	if err != nil {
+		c.Reply("**ERROR**: " + err.Error())
+		return
+	}
+	if response == "" {
+		c.Reply("Command sent.")
+		return
+	}
+	chunks := discordutil.SplitMessage(response, discordutil.MaxMessageLength)
+	if converted, ok := relay.ToANSI(response); ok {
+		chunks = discordutil.CodeBlocks(converted, "ansi")
+	}
+	for _, chunk := range chunks {
+		c.Reply(chunk)
+	}
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+)
+
+// fakeServer is up from the given check onwards.
+type fakeServer struct {
+	mcbot.Server
+	checks int
+	upAt   int
+}
+
+func (f *fakeServer) Stats(ctx context.Context) (mcbot.ServerStats, error) {
+	f.checks++
+	return mcbot.ServerStats{Running: f.checks >= f.upAt}, nil
+}
+
+func TestWaitForStart(t *testing.T) {
+	if err := waitForStart(context.Background(), &fakeServer{upAt: 2}, 5*time.Second); err != nil {
+		t.Errorf("got %v for a server that comes up", err)
+	}
+	if err := waitForStart(context.Background(), &fakeServer{upAt: 100}, time.Second); err == nil {
+		t.Error("got no error for a server that stays down")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := waitForStart(ctx, &fakeServer{upAt: 100}, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v after cancelling, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Progress is a single message edited as a long action works through its steps, instead
+// of one message per step.
+type Progress struct {
+	s         *discordgo.Session
+	channelID string
+	messageID string
+	title     string
+	steps     []string
+	current   int
+	result    string
+}
+
+// StartProgress posts the checklist for steps with the first one underway.
+func StartProgress(s *discordgo.Session, channelID string, title string, steps ...string) *Progress {
+	p := &Progress{s: s, channelID: channelID, title: title, steps: steps}
+	msg, err := s.ChannelMessageSend(channelID, p.render())
+	if err != nil {
+		fmt.Println("Error posting progress:", err)
+		return p
+	}
+	p.messageID = msg.ID
+	return p
+}
+
+// Next marks the current step done and starts the following one.
+func (p *Progress) Next() {
+	p.current++
+	p.update()
+}
+
+// Done marks every step done and shows result.
+func (p *Progress) Done(result string) {
+	p.current = len(p.steps)
+	p.result = result
+	p.update()
+}
+
+// Fail marks the current step failed and returns err, for use in return statements.
+func (p *Progress) Fail(err error) error {
+	p.result = "**FAILED**: " + err.Error()
+	p.update()
+	return err
+}
+
+func (p *Progress) update() {
+	if p.messageID == "" {
+		return
+	}
+	if _, err := p.s.ChannelMessageEdit(p.channelID, p.messageID, p.render()); err != nil {
+		fmt.Println("Error updating progress:", err)
+	}
+}
+
+func (p *Progress) render() string {
+	var sb strings.Builder
+	sb.WriteString(p.title + ":\n")
+	for i, step := range p.steps {
+		switch {
+		case i < p.current:
+			sb.WriteString("✅ ")
+		case i == p.current && p.result != "":
+			sb.WriteString("❌ ")
+		case i == p.current:
+			sb.WriteString("⏳ ")
+		default:
+			sb.WriteString("▫️ ")
+		}
+		sb.WriteString(step + "\n")
+	}
+	if p.result != "" {
+		sb.WriteString(p.result)
+	}
+	return sb.String()
+}
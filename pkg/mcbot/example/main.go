@@ -0,0 +1,54 @@
+// Command example is a minimal bot built on mcbot with its own command set.
+//
+//	DISCORD_TOKEN=... go run ./example
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+)
+
+func main() {
+	router := mcbot.NewRouter('!')
+	router.Handle(func(c *mcbot.Context) {
+		c.Reply("Pong!")
+	}, "ping")
+	router.Handle(func(c *mcbot.Context) {
+		c.Reply("Commands: " + strings.Join(router.Commands(), ", "))
+	}, "help", "commands")
+	router.NotFound = func(c *mcbot.Context) {
+		c.Reply("Unknown command `" + c.Name + "`, try `!help`")
+	}
+
+	bot, err := mcbot.New(mcbot.Options{
+		Token:   os.Getenv("DISCORD_TOKEN"),
+		Intents: discordgo.IntentsGuildMessages | discordgo.IntentMessageContent,
+		Router:  router,
+	})
+	if err != nil {
+		fmt.Println("Error creating bot:", err)
+		os.Exit(1)
+	}
+
+	bot.Go(func(s *discordgo.Session) {
+		for range time.Tick(time.Minute) {
+			s.UpdateGameStatus(0, "Minecraft at "+time.Now().Format("15:04"))
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	fmt.Println("Bot is now running.  Press CTRL-C to exit.")
+	if err = bot.Run(ctx); err != nil {
+		fmt.Println("Error running bot:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/hunterjsb/xn-mc/pkg/mcbot
+
+go 1.21.3
+
+require (
+	github.com/bwmarrin/discordgo v0.27.1
+	github.com/gorilla/websocket v1.4.2
+	github.com/hunterjsb/xn-mc/pkg/discordutil v0.0.0
+)
+
+require (
+	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b // indirect
+	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68 // indirect
+)
+
+replace github.com/hunterjsb/xn-mc/pkg/discordutil => ../discordutil
@@ -0,0 +1,133 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Provider updates the A record of a hostname.
+type Provider interface {
+	UpdateA(ctx context.Context, host string, ip net.IP) error
+}
+
+// Cloudflare updates an existing record through the Cloudflare API, with a token allowed to
+// edit the zone's DNS.
+type Cloudflare struct {
+	Token    string
+	ZoneID   string
+	RecordID string
+}
+
+func (c *Cloudflare) UpdateA(ctx context.Context, host string, ip net.IP) error {
+	body, err := json.Marshal(map[string]any{
+		"type":    "A",
+		"name":    host,
+		"content": ip.String(),
+		"ttl":     60,
+		"proxied": false,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/dns_records/%s", c.ZoneID, c.RecordID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("cloudflare: %s", resp.Status)
+	}
+	if !result.Success {
+		return fmt.Errorf("cloudflare: %s %v", resp.Status, result.Errors)
+	}
+	return nil
+}
+
+// DDNSOptions configures RunDDNS.
+type DDNSOptions struct {
+	Options
+	Provider Provider
+
+	// Interval between public IP checks (default 5 minutes)
+	Interval time.Duration
+
+	// OnUpdate is called after the record is pointed at a new IP, with whether the resolver
+	// already returns it. OnPropagated is called when an update that had not propagated
+	// does, and OnError when an update fails. Any may be nil.
+	OnUpdate     func(old net.IP, ip net.IP, propagated bool)
+	OnPropagated func(ip net.IP)
+	OnError      func(ip net.IP, err error)
+}
+
+// RunDDNS points the hostname at the public IP whenever it changes. It returns at the first
+// check after ctx is done.
+func RunDDNS(ctx context.Context, opts DDNSOptions) {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+
+	var lastIP net.IP
+	pending := false
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for ; ctx.Err() == nil; <-ticker.C {
+		ip, err := PublicIP()
+		if err != nil {
+			fmt.Println("Error reading public IP:", err)
+			continue
+		}
+
+		if pending && Resolves(opts.Options, ip) {
+			pending = false
+			if opts.OnPropagated != nil {
+				opts.OnPropagated(ip)
+			}
+		}
+		if ip.Equal(lastIP) {
+			continue
+		}
+		if lastIP == nil && Resolves(opts.Options, ip) {
+			lastIP = ip
+			continue
+		}
+
+		updateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err = opts.Provider.UpdateA(updateCtx, opts.Hostname, ip)
+		cancel()
+		if err != nil {
+			if opts.OnError != nil {
+				opts.OnError(ip, err)
+			}
+			continue
+		}
+
+		old := lastIP
+		lastIP = ip
+		pending = !Resolves(opts.Options, ip)
+		if opts.OnUpdate != nil {
+			opts.OnUpdate(old, ip, !pending)
+		}
+	}
+}
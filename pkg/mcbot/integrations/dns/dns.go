@@ -0,0 +1,174 @@
+// Package dns watches the A record a Minecraft server's hostname resolves to, and keeps it
+// pointed at the host's public IP through a DNS provider's API.
+package dns
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ARecord is an IPv4 address record with its TTL.
+type ARecord struct {
+	IP  net.IP
+	TTL time.Duration
+}
+
+// Options names the record to check.
+type Options struct {
+	Hostname string
+
+	// Resolver is the DNS server asked, as host:port (default 1.1.1.1:53)
+	Resolver string
+}
+
+func (o Options) resolver() string {
+	if o.Resolver == "" {
+		return "1.1.1.1:53"
+	}
+	return o.Resolver
+}
+
+// PublicIP asks an external service for the host's public IPv4 address.
+func PublicIP() (net.IP, error) {
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get("https://api.ipify.org")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return nil, fmt.Errorf("unexpected public IP response: %q", body)
+	}
+	return ip, nil
+}
+
+// LookupA queries the resolver directly for A records, since net.Resolver does not expose TTLs.
+func LookupA(host string, resolver string) ([]ARecord, error) {
+	conn, err := net.DialTimeout("udp", resolver, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// Header: ID, flags (recursion desired), one question
+	id := uint16(rand.Intn(1 << 16))
+	query := binary.BigEndian.AppendUint16(nil, id)
+	query = append(query, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0)
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		query = append(query, byte(len(label)))
+		query = append(query, label...)
+	}
+	query = append(query, 0, 0, 1, 0, 1) // root, QTYPE A, QCLASS IN
+
+	if _, err = conn.Write(query); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, 1500)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return nil, err
+	}
+	return parseARecords(resp[:n], id)
+}
+
+// parseARecords decodes the A records in a DNS response to the query with the given ID.
+func parseARecords(resp []byte, id uint16) ([]ARecord, error) {
+	if len(resp) < 12 || binary.BigEndian.Uint16(resp) != id {
+		return nil, fmt.Errorf("malformed DNS response")
+	}
+	if rcode := resp[3] & 0x0f; rcode != 0 {
+		return nil, fmt.Errorf("DNS query failed with rcode %d", rcode)
+	}
+	questions, answers := binary.BigEndian.Uint16(resp[4:]), binary.BigEndian.Uint16(resp[6:])
+
+	var err error
+	offset := 12
+	for i := 0; i < int(questions); i++ {
+		if offset, err = skipDNSName(resp, offset); err != nil {
+			return nil, err
+		}
+		offset += 4
+	}
+
+	var records []ARecord
+	for i := 0; i < int(answers); i++ {
+		if offset, err = skipDNSName(resp, offset); err != nil {
+			return nil, err
+		}
+		if offset+10 > len(resp) {
+			return nil, fmt.Errorf("truncated DNS answer")
+		}
+		rtype := binary.BigEndian.Uint16(resp[offset:])
+		ttl := binary.BigEndian.Uint32(resp[offset+4:])
+		length := int(binary.BigEndian.Uint16(resp[offset+8:]))
+		offset += 10
+		if offset+length > len(resp) {
+			return nil, fmt.Errorf("truncated DNS answer")
+		}
+		if rtype == 1 && length == 4 {
+			records = append(records, ARecord{IP: net.IP(resp[offset : offset+4]), TTL: time.Duration(ttl) * time.Second})
+		}
+		offset += length
+	}
+	return records, nil
+}
+
+// skipDNSName returns the offset just past the (possibly compressed) name at offset.
+func skipDNSName(msg []byte, offset int) (int, error) {
+	for offset < len(msg) {
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			return offset + 1, nil
+		case length&0xc0 == 0xc0:
+			return offset + 2, nil
+		default:
+			offset += length + 1
+		}
+	}
+	return 0, fmt.Errorf("truncated DNS name")
+}
+
+// Check compares the hostname's A records to the public IP and describes any drift.
+func Check(opts Options) (records []ARecord, ip net.IP, drift string, err error) {
+	if records, err = LookupA(opts.Hostname, opts.resolver()); err != nil {
+		return nil, nil, "", err
+	}
+	if ip, err = PublicIP(); err != nil {
+		return records, nil, "", err
+	}
+
+	for _, record := range records {
+		if record.IP.Equal(ip) {
+			return records, ip, "", nil
+		}
+	}
+	return records, ip, fmt.Sprintf("%s does not point at the current public IP %s", opts.Hostname, ip), nil
+}
+
+// Resolves reports whether the hostname has an A record for ip. Lookup errors count as no.
+func Resolves(opts Options, ip net.IP) bool {
+	records, err := LookupA(opts.Hostname, opts.resolver())
+	if err != nil {
+		return false
+	}
+	for _, record := range records {
+		if record.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
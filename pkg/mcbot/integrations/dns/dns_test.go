@@ -0,0 +1,75 @@
+package dns
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureID is the query ID every response in testdata answers.
+const fixtureID = 0xabcd
+
+func readDNSFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseARecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    []ARecord
+	}{
+		{"two A records", "a_records.bin", []ARecord{
+			{IP: net.IPv4(93, 184, 215, 14).To4(), TTL: 300 * time.Second},
+			{IP: net.IPv4(93, 184, 216, 34).To4(), TTL: 300 * time.Second},
+		}},
+		{"CNAME is skipped", "cname.bin", []ARecord{
+			{IP: net.IPv4(140, 82, 121, 4).To4(), TTL: time.Minute},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseARecords(readDNSFixture(t, test.fixture), fixtureID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseARecordsErrors(t *testing.T) {
+	records := readDNSFixture(t, "a_records.bin")
+	tests := []struct {
+		name string
+		resp []byte
+		id   uint16
+		want string
+	}{
+		{"NXDOMAIN", readDNSFixture(t, "nxdomain.bin"), fixtureID, "rcode 3"},
+		{"wrong ID", records, fixtureID + 1, "malformed"},
+		{"short header", records[:11], fixtureID, "malformed"},
+		{"truncated question", records[:20], fixtureID, "truncated DNS name"},
+		{"truncated answer header", records[:len(records)-12], fixtureID, "truncated DNS answer"},
+		{"truncated answer data", records[:len(records)-2], fixtureID, "truncated DNS answer"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseARecords(test.resp, test.id)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got error %v, want one containing %q", err, test.want)
+			}
+		})
+	}
+}
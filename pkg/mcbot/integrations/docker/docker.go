@@ -0,0 +1,271 @@
+// Package docker runs a Minecraft server as a Docker container through the Engine API, as
+// run with the itzg/minecraft-server image.
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+)
+
+// Options configures a Controller.
+type Options struct {
+	// Host is the Engine API address, unix:// or tcp:// (default unix:///var/run/docker.sock)
+	Host string
+
+	// Container is the server's container name or ID (default mc)
+	Container string
+}
+
+// Controller manages the server container. It implements mcbot.Server, with console commands
+// going through the image's rcon-cli.
+type Controller struct {
+	client    *http.Client
+	baseURL   string
+	container string
+}
+
+// New connects to the Engine API at opts.Host.
+func New(opts Options) (*Controller, error) {
+	host, container := opts.Host, opts.Container
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+	if container == "" {
+		container = "mc"
+	}
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Controller{container: container}
+	switch parsed.Scheme {
+	case "unix":
+		d.baseURL = "http://docker"
+		d.client = &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", parsed.Path)
+			},
+		}}
+	case "tcp", "http":
+		d.baseURL = "http://" + parsed.Host
+		d.client = http.DefaultClient
+	default:
+		return nil, fmt.Errorf("docker: unsupported host %q", host)
+	}
+	return d, nil
+}
+
+func (d *Controller) do(ctx context.Context, method string, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, d.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// 304 is returned for starting a running container or stopping a stopped one
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotModified {
+		defer resp.Body.Close()
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, fmt.Errorf("docker: %s %s", resp.Status, apiErr.Message)
+	}
+	return resp, nil
+}
+
+func (d *Controller) request(ctx context.Context, method string, path string, body any, result any) error {
+	resp, err := d.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (d *Controller) containerPath(suffix string) string {
+	return "/containers/" + url.PathEscape(d.container) + suffix
+}
+
+func (d *Controller) Start(ctx context.Context) error {
+	return d.request(ctx, http.MethodPost, d.containerPath("/start"), nil, nil)
+}
+
+// Stop gives the server a minute to save the world before the container is killed.
+func (d *Controller) Stop(ctx context.Context) error {
+	return d.request(ctx, http.MethodPost, d.containerPath("/stop?t=60"), nil, nil)
+}
+
+func (d *Controller) Restart(ctx context.Context) error {
+	return d.request(ctx, http.MethodPost, d.containerPath("/restart?t=60"), nil, nil)
+}
+
+// inspection is the part of a container inspection the controller uses.
+type inspection struct {
+	State struct {
+		Running   bool
+		StartedAt time.Time
+	}
+	Config struct {
+		Tty bool
+	}
+}
+
+func (d *Controller) inspect(ctx context.Context) (inspection, error) {
+	var info inspection
+	err := d.request(ctx, http.MethodGet, d.containerPath("/json"), nil, &info)
+	return info, err
+}
+
+func (d *Controller) Stats(ctx context.Context) (mcbot.ServerStats, error) {
+	info, err := d.inspect(ctx)
+	if err != nil || !info.State.Running {
+		return mcbot.ServerStats{}, err
+	}
+	stats := mcbot.ServerStats{Running: true, Uptime: time.Since(info.State.StartedAt)}
+
+	var usage struct {
+		MemoryStats struct {
+			Usage int64            `json:"usage"`
+			Stats map[string]int64 `json:"stats"`
+		} `json:"memory_stats"`
+	}
+	if err = d.request(ctx, http.MethodGet, d.containerPath("/stats?stream=false"), nil, &usage); err != nil {
+		return stats, err
+	}
+	// Page cache counts towards usage but not towards what the JVM holds: inactive_file on
+	// cgroup v2, cache on v1
+	memory := usage.MemoryStats.Usage - usage.MemoryStats.Stats["inactive_file"] - usage.MemoryStats.Stats["cache"]
+	stats.MemoryKB = int(max(memory, 0) / 1024)
+	return stats, nil
+}
+
+// SendCommand runs cmd with rcon-cli inside the container and returns its output.
+func (d *Controller) SendCommand(ctx context.Context, cmd string) (string, error) {
+	var exec struct {
+		ID string `json:"Id"`
+	}
+	err := d.request(ctx, http.MethodPost, d.containerPath("/exec"), map[string]any{
+		"Cmd":          []string{"rcon-cli", cmd},
+		"AttachStdout": true,
+		"AttachStderr": true,
+	}, &exec)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := d.do(ctx, http.MethodPost, "/exec/"+exec.ID+"/start", map[string]bool{"Detach": false, "Tty": false})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out strings.Builder
+	err = demuxStream(resp.Body, func(line string) { out.WriteString(line + "\n") })
+	return strings.TrimSpace(out.String()), err
+}
+
+// Logs follows the container's output, reconnecting whenever the stream ends, as it does
+// when the container stops.
+func (d *Controller) Logs(ctx context.Context) (<-chan string, error) {
+	lines := make(chan string, 256)
+	go func() {
+		defer close(lines)
+		since := time.Now()
+		for {
+			following := time.Now()
+			if err := d.followLogs(ctx, since, lines); err != nil {
+				fmt.Println("Error following container logs:", err)
+			}
+			since = following
+
+			select {
+			case <-time.After(10 * time.Second):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return lines, nil
+}
+
+func (d *Controller) followLogs(ctx context.Context, since time.Time, lines chan<- string) error {
+	info, err := d.inspect(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := d.do(ctx, http.MethodGet, d.containerPath(fmt.Sprintf("/logs?follow=true&stdout=true&stderr=true&since=%d", since.Unix())), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	send := func(line string) { lines <- mcbot.StripColors(strings.TrimSuffix(line, "\r")) }
+	// Containers with a TTY stream raw output, others prefix each frame with a header
+	if !info.Config.Tty {
+		return demuxStream(resp.Body, send)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		send(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// demuxStream splits a multiplexed stdout/stderr stream into lines. Each frame has an
+// 8 byte header: the stream type, three zero bytes and the big endian payload size.
+func demuxStream(r io.Reader, line func(string)) error {
+	var partial string
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if partial != "" {
+				line(partial)
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+
+		parts := strings.Split(partial+string(payload), "\n")
+		partial = parts[len(parts)-1]
+		for _, part := range parts[:len(parts)-1] {
+			line(part)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/iotest"
+)
+
+// dockerFrame encodes payload as one frame of a multiplexed log stream.
+func dockerFrame(stream byte, payload string) []byte {
+	frame := binary.BigEndian.AppendUint32([]byte{stream, 0, 0, 0}, uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+func TestDemuxStream(t *testing.T) {
+	logs, err := os.ReadFile(filepath.Join("testdata", "logs.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	captured := []string{
+		"[12:00:00] [Server thread/INFO]: Starting minecraft server version 1.20.4",
+		`[12:00:01] [Server thread/INFO]: Preparing level "world"`,
+		`[12:00:05] [Server thread/INFO]: Done (4.2s)! For help, type "help"`,
+		"[12:00:06] [Server thread/WARN]: Can't keep up!",
+		"[12:00:10] [Server thread/INFO]: Steve joined the game",
+	}
+
+	tests := []struct {
+		name   string
+		stream io.Reader
+		want   []string
+		err    error
+	}{
+		{"captured logs", bytes.NewReader(logs), captured, nil},
+		{"captured logs one byte at a time", iotest.OneByteReader(bytes.NewReader(logs)), captured, nil},
+		{"empty stream", bytes.NewReader(nil), nil, nil},
+		{"line split across three frames", bytes.NewReader(bytes.Join([][]byte{
+			dockerFrame(1, "one "), dockerFrame(1, "two "), dockerFrame(1, "three\nfour\n"),
+		}, nil)), []string{"one two three", "four"}, nil},
+		{"empty frame", bytes.NewReader(append(dockerFrame(1, ""), dockerFrame(2, "err\n")...)), []string{"err"}, nil},
+		{"truncated header", bytes.NewReader(append(dockerFrame(1, "done\npart"), 1, 0)), []string{"done", "part"}, io.ErrUnexpectedEOF},
+		{"truncated payload", bytes.NewReader(dockerFrame(1, "cut off\n")[:12]), nil, io.ErrUnexpectedEOF},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			err := demuxStream(test.stream, func(line string) { got = append(got, line) })
+			if !errors.Is(err, test.err) {
+				t.Errorf("got error %v, want %v", err, test.err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got lines %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+// Package moderation is the warn, kick, ban, mute and case commands: actions are taken in
+// game over the console and on Discord, numbered as cases and posted to a mod log.
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+)
+
+// Case is a numbered moderation action.
+type Case struct {
+	Number      int
+	Time        time.Time
+	Action      string
+	Player      string
+	DiscordID   string
+	ModeratorID string
+	Moderator   string
+	Reason      string
+}
+
+// Store keeps the case list.
+type Store interface {
+	Load(cases *[]Case) error
+	Save(cases []Case) error
+}
+
+// File is a Store keeping the cases as JSON in the named file.
+type File string
+
+func (f File) Load(cases *[]Case) error {
+	data, err := os.ReadFile(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, cases)
+}
+
+func (f File) Save(cases []Case) error {
+	data, err := json.MarshalIndent(cases, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(string(f), data, 0644)
+}
+
+// Options configures a Moderator.
+type Options struct {
+	// Console runs the in-game commands, e.g. over rcon
+	Console func(cmd string) (string, error)
+
+	// IsAdmin decides who may moderate
+	IsAdmin func(c *mcbot.Context) bool
+
+	// Store keeps the cases (default the file cases.json)
+	Store Store
+
+	// LogChannelID is where cases are posted
+	LogChannelID string
+
+	// BannedRoleID, if set, is given to the member mentioned in a ban and removed on unban
+	BannedRoleID string
+}
+
+// Moderator handles the moderation commands.
+type Moderator struct {
+	opts Options
+	mu   sync.Mutex
+}
+
+func New(opts Options) (*Moderator, error) {
+	if opts.Console == nil || opts.IsAdmin == nil {
+		return nil, fmt.Errorf("moderation: Console and IsAdmin must be set")
+	}
+	if opts.Store == nil {
+		opts.Store = File("cases.json")
+	}
+	return &Moderator{opts: opts}, nil
+}
+
+// Register adds mc-warn, mc-kick, mc-ban, mc-unban, mute, case and history to r.
+func (mod *Moderator) Register(r *mcbot.Router) {
+	r.Handle(func(c *mcbot.Context) {
+		mod.handleAction(c, strings.TrimPrefix(c.Name, "mc-"))
+	}, "mc-warn", "mc-kick", "mc-ban", "mc-unban")
+	r.Handle(mod.handleMute, "mute")
+	r.Handle(mod.handleCase, "case")
+	r.Handle(mod.handleHistory, "history")
+}
+
+// Record numbers and stores a moderation action, then posts it to the mod log.
+func (mod *Moderator) Record(s *discordgo.Session, c Case) (Case, error) {
+	mod.mu.Lock()
+	var cases []Case
+	err := mod.opts.Store.Load(&cases)
+	if err == nil {
+		c.Number = len(cases) + 1
+		c.Time = time.Now()
+		cases = append(cases, c)
+		err = mod.opts.Store.Save(cases)
+	}
+	mod.mu.Unlock()
+	if err != nil {
+		return c, err
+	}
+
+	s.ChannelMessageSend(mod.opts.LogChannelID, formatCase(c))
+	return c, nil
+}
+
+func formatCase(c Case) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**CASE %d**: %s", c.Number, c.Action)
+	if c.Player != "" {
+		sb.WriteString(" " + c.Player)
+	}
+	if c.DiscordID != "" {
+		fmt.Fprintf(&sb, " (<@%s>)", c.DiscordID)
+	}
+	fmt.Fprintf(&sb, " by %s <t:%d:f>", c.Moderator, c.Time.Unix())
+	if c.Reason != "" {
+		sb.WriteString("\nReason: " + c.Reason)
+	}
+	return sb.String()
+}
+
+// parseArgs splits `<player> [@member] [reason...]`.
+func parseArgs(args []string) (player string, discordID string, reason string) {
+	player = args[0]
+	rest := args[1:]
+	if len(rest) > 0 && strings.HasPrefix(rest[0], "<@") && !strings.HasPrefix(rest[0], "<@&") {
+		discordID = mentionID(rest[0])
+		rest = rest[1:]
+	}
+	return player, discordID, strings.Join(rest, " ")
+}
+
+func mentionID(mention string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(mention, "<@"), "!"), ">")
+}
+
+// handleAction warns, kicks, bans or unbans a player in game and records the case. Bans also
+// assign BannedRoleID to the mentioned Discord member, and unbans remove it.
+func (mod *Moderator) handleAction(c *mcbot.Context, action string) {
+	if !mod.opts.IsAdmin(c) {
+		c.Reply("Only admins can " + action + " players.")
+		return
+	}
+	if len(c.Args) == 0 || (action != "unban" && len(c.Args) < 2) {
+		c.Reply(fmt.Sprintf("Usage: `mc-%s <player> [@member] <reason>`", action))
+		return
+	}
+	player, discordID, reason := parseArgs(c.Args)
+
+	var command string
+	switch action {
+	case "warn":
+		text, _ := json.Marshal(map[string]string{"text": "Warning from staff: " + reason, "color": "red"})
+		command = fmt.Sprintf("tellraw %s %s", player, text)
+	case "kick", "ban":
+		command = fmt.Sprintf("%s %s %s", action, player, reason)
+	case "unban":
+		command = "pardon " + player
+	}
+	response, err := mod.opts.Console(strings.TrimSpace(command))
+	if err != nil {
+		c.Reply("Failed to " + action + " in game: " + err.Error())
+		return
+	}
+
+	if roleID := mod.opts.BannedRoleID; roleID != "" && discordID != "" && (action == "ban" || action == "unban") {
+		if action == "unban" {
+			err = c.Session.GuildMemberRoleRemove(c.Message.GuildID, discordID, roleID)
+		} else {
+			err = c.Session.GuildMemberRoleAdd(c.Message.GuildID, discordID, roleID)
+		}
+		if err != nil {
+			c.Reply("Failed to update the Banned role: " + err.Error())
+		}
+	}
+
+	recorded, err := mod.Record(c.Session, Case{
+		Action:      action,
+		Player:      player,
+		DiscordID:   discordID,
+		ModeratorID: c.Message.Author.ID,
+		Moderator:   c.Message.Author.Username,
+		Reason:      reason,
+	})
+	if err != nil {
+		c.Reply("Failed to record case: " + err.Error())
+		return
+	}
+	c.Reply(fmt.Sprintf("%s\nCase %d recorded.", response, recorded.Number))
+}
+
+// handleMute times a Discord member out, since vanilla has no in-game mute.
+func (mod *Moderator) handleMute(c *mcbot.Context) {
+	usage := "Usage: `mute @member <duration> <reason>`"
+	if !mod.opts.IsAdmin(c) {
+		c.Reply("Only admins can mute members.")
+		return
+	}
+	if len(c.Args) < 3 || !strings.HasPrefix(c.Args[0], "<@") {
+		c.Reply(usage)
+		return
+	}
+	duration, err := time.ParseDuration(c.Args[1])
+	if err != nil || duration <= 0 {
+		c.Reply(usage)
+		return
+	}
+	discordID := mentionID(c.Args[0])
+
+	until := time.Now().Add(duration)
+	if err = c.Session.GuildMemberTimeout(c.Message.GuildID, discordID, &until); err != nil {
+		c.Reply("Failed to mute member: " + err.Error())
+		return
+	}
+
+	recorded, err := mod.Record(c.Session, Case{
+		Action:      "mute " + duration.String(),
+		DiscordID:   discordID,
+		ModeratorID: c.Message.Author.ID,
+		Moderator:   c.Message.Author.Username,
+		Reason:      strings.Join(c.Args[2:], " "),
+	})
+	if err != nil {
+		c.Reply("Failed to record case: " + err.Error())
+		return
+	}
+	c.Reply(fmt.Sprintf("Muted until <t:%d:f>. Case %d recorded.", until.Unix(), recorded.Number))
+}
+
+// handleCase shows a case or edits its reason.
+func (mod *Moderator) handleCase(c *mcbot.Context) {
+	usage := "Usage: `case view <n>`, `case edit <n> <reason>`"
+	args := c.Args
+	if !mod.opts.IsAdmin(c) {
+		c.Reply("Only admins can view cases.")
+		return
+	}
+	if len(args) < 2 || (args[0] != "view" && args[0] != "edit") || (args[0] == "edit" && len(args) < 3) {
+		c.Reply(usage)
+		return
+	}
+	number, err := strconv.Atoi(args[1])
+	if err != nil {
+		c.Reply(usage)
+		return
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	var cases []Case
+	if err = mod.opts.Store.Load(&cases); err != nil {
+		c.Reply("Failed to read cases: " + err.Error())
+		return
+	}
+	if number < 1 || number > len(cases) {
+		c.Reply(fmt.Sprintf("No case %d.", number))
+		return
+	}
+	found := &cases[number-1]
+
+	if args[0] == "edit" {
+		found.Reason = strings.Join(args[2:], " ")
+		if err = mod.opts.Store.Save(cases); err != nil {
+			c.Reply("Failed to save case: " + err.Error())
+			return
+		}
+		c.Session.ChannelMessageSend(mod.opts.LogChannelID, fmt.Sprintf("Case %d reason edited by %s", found.Number, c.Message.Author.Username))
+	}
+	c.Reply(formatCase(*found))
+}
+
+// handleHistory lists the cases for a player name or mentioned member.
+func (mod *Moderator) handleHistory(c *mcbot.Context) {
+	if !mod.opts.IsAdmin(c) {
+		c.Reply("Only admins can view moderation history.")
+		return
+	}
+	if len(c.Args) != 1 {
+		c.Reply("Usage: `history <player|@member>`")
+		return
+	}
+	discordID := mentionID(c.Args[0])
+
+	mod.mu.Lock()
+	var cases []Case
+	err := mod.opts.Store.Load(&cases)
+	mod.mu.Unlock()
+	if err != nil {
+		c.Reply("Failed to read cases: " + err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "HISTORY: %s\n", c.Args[0])
+	found := false
+	for _, recorded := range cases {
+		if strings.EqualFold(recorded.Player, c.Args[0]) || recorded.DiscordID == discordID {
+			found = true
+			fmt.Fprintf(&sb, "#%d <t:%d:d> %s by %s: %s\n", recorded.Number, recorded.Time.Unix(), recorded.Action, recorded.Moderator, recorded.Reason)
+		}
+	}
+	if !found {
+		sb.WriteString("No cases.")
+	}
+	for _, chunk := range discordutil.SplitMessage(sb.String(), discordutil.MaxMessageLength) {
+		c.Reply(chunk)
+	}
+}
@@ -0,0 +1,46 @@
+package moderation
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args      []string
+		player    string
+		discordID string
+		reason    string
+	}{
+		{[]string{"Steve", "griefing", "spawn"}, "Steve", "", "griefing spawn"},
+		{[]string{"Steve", "<@!123>", "griefing"}, "Steve", "123", "griefing"},
+		{[]string{"Steve", "<@456>"}, "Steve", "456", ""},
+		{[]string{"Steve", "<@&789>", "role pings"}, "Steve", "", "<@&789> role pings"},
+	}
+	for _, test := range tests {
+		player, discordID, reason := parseArgs(test.args)
+		if player != test.player || discordID != test.discordID || reason != test.reason {
+			t.Errorf("parseArgs(%q) = %q, %q, %q, want %q, %q, %q", test.args, player, discordID, reason, test.player, test.discordID, test.reason)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	store := File(filepath.Join(t.TempDir(), "cases.json"))
+	var cases []Case
+	if err := store.Load(&cases); err != nil || len(cases) != 0 {
+		t.Fatalf("got %v, %v from a missing file, want no cases", cases, err)
+	}
+
+	want := []Case{{Number: 1, Action: "ban", Player: "Steve", Reason: "griefing"}}
+	if err := store.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Load(&cases); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cases, want) {
+		t.Errorf("got %+v, want %+v", cases, want)
+	}
+}
@@ -0,0 +1,212 @@
+// Package pterodactyl runs a Minecraft server on a Pterodactyl panel through its client API.
+package pterodactyl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hunterjsb/xn-mc/pkg/mcbot"
+)
+
+// Options configures a Controller.
+type Options struct {
+	// URL is the panel's address, e.g. https://panel.example.com
+	URL string
+
+	// APIKey is a client API key from the panel's account settings
+	APIKey string
+
+	// ServerID is the server's identifier, as in the panel's URL for it
+	ServerID string
+}
+
+// Controller drives a server on a Pterodactyl panel. It implements mcbot.Server.
+type Controller struct {
+	baseURL  string
+	apiKey   string
+	serverID string
+}
+
+// New returns a Controller for the server in opts.
+func New(opts Options) (*Controller, error) {
+	if opts.URL == "" || opts.ServerID == "" {
+		return nil, fmt.Errorf("pterodactyl: URL and ServerID must be set")
+	}
+	return &Controller{baseURL: opts.URL, apiKey: opts.APIKey, serverID: opts.ServerID}, nil
+}
+
+func (p *Controller) request(ctx context.Context, method string, path string, body any, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(p.baseURL, "/")+"/api/client/servers/"+p.serverID+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && len(apiErr.Errors) > 0 {
+			return fmt.Errorf("pterodactyl: %s %s", resp.Status, apiErr.Errors[0].Detail)
+		}
+		return fmt.Errorf("pterodactyl: %s", resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (p *Controller) power(ctx context.Context, signal string) error {
+	return p.request(ctx, http.MethodPost, "/power", map[string]string{"signal": signal}, nil)
+}
+
+func (p *Controller) Start(ctx context.Context) error {
+	return p.power(ctx, "start")
+}
+
+func (p *Controller) Stop(ctx context.Context) error {
+	return p.power(ctx, "stop")
+}
+
+func (p *Controller) Restart(ctx context.Context) error {
+	return p.power(ctx, "restart")
+}
+
+func (p *Controller) Stats(ctx context.Context) (mcbot.ServerStats, error) {
+	var result struct {
+		Attributes struct {
+			CurrentState string `json:"current_state"`
+			Resources    struct {
+				MemoryBytes int64 `json:"memory_bytes"`
+				Uptime      int64 `json:"uptime"` // milliseconds
+			} `json:"resources"`
+		} `json:"attributes"`
+	}
+	if err := p.request(ctx, http.MethodGet, "/resources", nil, &result); err != nil {
+		return mcbot.ServerStats{}, err
+	}
+	return mcbot.ServerStats{
+		Running:  result.Attributes.CurrentState != "offline",
+		Uptime:   time.Duration(result.Attributes.Resources.Uptime) * time.Millisecond,
+		MemoryKB: int(result.Attributes.Resources.MemoryBytes / 1024),
+	}, nil
+}
+
+// SendCommand runs cmd on the console. The panel does not return command output, it only
+// shows up in the console stream.
+func (p *Controller) SendCommand(ctx context.Context, cmd string) (string, error) {
+	return "", p.request(ctx, http.MethodPost, "/command", map[string]string{"command": cmd}, nil)
+}
+
+// websocketEvent is a message on the panel's console websocket.
+type websocketEvent struct {
+	Event string   `json:"event"`
+	Args  []string `json:"args,omitempty"`
+}
+
+func (p *Controller) websocketCredentials(ctx context.Context) (token string, socket string, err error) {
+	var result struct {
+		Data struct {
+			Token  string `json:"token"`
+			Socket string `json:"socket"`
+		} `json:"data"`
+	}
+	err = p.request(ctx, http.MethodGet, "/websocket", nil, &result)
+	return result.Data.Token, result.Data.Socket, err
+}
+
+// Logs follows the console websocket, reconnecting whenever it drops.
+func (p *Controller) Logs(ctx context.Context) (<-chan string, error) {
+	lines := make(chan string, 256)
+	go func() {
+		defer close(lines)
+		for {
+			if err := p.followConsole(ctx, lines); err != nil {
+				fmt.Println("Error following Pterodactyl console:", err)
+			}
+			select {
+			case <-time.After(10 * time.Second):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return lines, nil
+}
+
+func (p *Controller) followConsole(ctx context.Context, lines chan<- string) error {
+	token, socket, err := p.websocketCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	// Wings only accepts connections from the panel's origin
+	conn, _, err := websocket.DefaultDialer.Dial(socket, http.Header{"Origin": {strings.TrimSuffix(p.baseURL, "/")}})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// Unblocks ReadJSON on cancellation; done stops it once this connection is finished
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err = conn.WriteJSON(websocketEvent{Event: "auth", Args: []string{token}}); err != nil {
+		return err
+	}
+	for {
+		var event websocketEvent
+		if err = conn.ReadJSON(&event); err != nil {
+			return err
+		}
+		switch event.Event {
+		case "console output":
+			for _, line := range event.Args {
+				select {
+				case lines <- mcbot.StripColors(line):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		case "token expiring":
+			if token, _, err = p.websocketCredentials(ctx); err != nil {
+				return err
+			}
+			if err = conn.WriteJSON(websocketEvent{Event: "auth", Args: []string{token}}); err != nil {
+				return err
+			}
+		case "token expired", "jwt error":
+			return fmt.Errorf("pterodactyl: websocket %s", event.Event)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package monitor checks whether a Minecraft server is up: a Server List Ping client and a
+// watcher that reports when the server goes down and comes back.
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// Options configures Watch.
+type Options struct {
+	// Running reports whether the server is up. Defaults to pinging Address.
+	Running func() bool
+
+	// Address is the host:port pinged when Running is not set
+	Address string
+
+	// Interval between checks (default 30 seconds)
+	Interval time.Duration
+
+	// OnDown is called when a server that was running is found stopped, OnUp when it is
+	// running again. Either may be nil.
+	OnDown func()
+	OnUp   func()
+}
+
+// Watch checks the server every Interval until ctx is done and reports each change.
+func Watch(ctx context.Context, opts Options) {
+	if opts.Running == nil {
+		address := opts.Address
+		opts.Running = func() bool {
+			_, err := Ping(address)
+			return err == nil
+		}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+
+	wasRunning := opts.Running()
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		running := opts.Running()
+		if wasRunning && !running && opts.OnDown != nil {
+			opts.OnDown()
+		}
+		if !wasRunning && running && opts.OnUp != nil {
+			opts.OnUp()
+		}
+		wasRunning = running
+	}
+}
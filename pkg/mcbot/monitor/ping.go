@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status is the JSON a server answers a Server List Ping with.
+type Status struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Online int `json:"online"`
+		Max    int `json:"max"`
+	} `json:"players"`
+	// Description is either a plain string or a chat component
+	Description json.RawMessage `json:"description"`
+}
+
+// Ping performs a Server List Ping against address (host:port).
+func Ping(address string) (Status, error) {
+	var res Status
+
+	host, rawPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return res, err
+	}
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		return res, err
+	}
+
+	conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+	if err != nil {
+		return res, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// Handshake (protocol -1, next state status) followed by a status request
+	var handshake bytes.Buffer
+	handshake.WriteByte(0x00)
+	handshake.Write(binary.AppendUvarint(nil, uint64(uint32(0xffffffff))))
+	handshake.Write(binary.AppendUvarint(nil, uint64(len(host))))
+	handshake.WriteString(host)
+	handshake.Write(binary.BigEndian.AppendUint16(nil, uint16(port)))
+	handshake.WriteByte(0x01)
+
+	if err = writePacket(conn, handshake.Bytes()); err != nil {
+		return res, err
+	}
+	if err = writePacket(conn, []byte{0x00}); err != nil {
+		return res, err
+	}
+
+	r := bufio.NewReader(conn)
+	if _, err = binary.ReadUvarint(r); err != nil { // packet length
+		return res, err
+	}
+	if id, err := binary.ReadUvarint(r); err != nil || id != 0 {
+		return res, fmt.Errorf("unexpected status response packet %d: %v", id, err)
+	}
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return res, err
+	}
+	if length > maxStatusLength {
+		return res, fmt.Errorf("status response of %d bytes is too long", length)
+	}
+	payload := make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return res, err
+	}
+	err = json.Unmarshal(payload, &res)
+	return res, err
+}
+
+// maxStatusLength is the longest string the protocol allows: 32767 characters of up to 3 bytes.
+const maxStatusLength = 32767 * 3
+
+func writePacket(w io.Writer, data []byte) error {
+	_, err := w.Write(append(binary.AppendUvarint(nil, uint64(len(data))), data...))
+	return err
+}
+
+// MOTD flattens the description into plain text without formatting codes.
+func (p Status) MOTD() string {
+	var text string
+	if json.Unmarshal(p.Description, &text) != nil {
+		var component struct {
+			Text  string `json:"text"`
+			Extra []struct {
+				Text string `json:"text"`
+			} `json:"extra"`
+		}
+		json.Unmarshal(p.Description, &component)
+		text = component.Text
+		for _, extra := range component.Extra {
+			text += extra.Text
+		}
+	}
+
+	return stripFormatting(text)
+}
+
+// stripFormatting drops §x color and style codes.
+func stripFormatting(text string) string {
+	var sb strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '§' {
+			i++
+			continue
+		}
+		sb.WriteRune(runes[i])
+	}
+	return sb.String()
+}
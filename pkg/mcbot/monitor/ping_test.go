@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// serveStatus answers one Server List Ping on a local port with a status string of the
+// given declared length and returns the address.
+func serveStatus(t *testing.T, status string, declared int) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Handshake and status request
+		r := bufio.NewReader(conn)
+		for i := 0; i < 2; i++ {
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return
+			}
+			io.CopyN(io.Discard, r, int64(length))
+		}
+
+		payload := append([]byte{0x00}, binary.AppendUvarint(nil, uint64(declared))...)
+		payload = append(payload, status...)
+		conn.Write(append(binary.AppendUvarint(nil, uint64(len(payload))), payload...))
+	}()
+	return listener.Addr().String()
+}
+
+func TestPing(t *testing.T) {
+	status := `{"version":{"name":"1.20.4","protocol":765},"players":{"online":3,"max":20},` +
+		`"description":{"text":"§aXandaris ","extra":[{"text":"SMP"}]}}`
+	got, err := Ping(serveStatus(t, status, len(status)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version.Name != "1.20.4" || got.Players.Online != 3 || got.Players.Max != 20 {
+		t.Errorf("got %+v", got)
+	}
+	if motd := got.MOTD(); motd != "Xandaris SMP" {
+		t.Errorf("got MOTD %q, want %q", motd, "Xandaris SMP")
+	}
+}
+
+func TestPingStatusTooLong(t *testing.T) {
+	_, err := Ping(serveStatus(t, "", maxStatusLength+1))
+	if err == nil || !strings.Contains(err.Error(), "too long") {
+		t.Errorf("got error %v, want the status to be rejected as too long", err)
+	}
+}
@@ -0,0 +1,103 @@
+package relay
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const ansiReset = "\u001b[0m"
+
+// ansiCodes maps §x formatting codes to the ANSI codes Discord's ansi code blocks support.
+// Discord only has eight colors, so the light and dark variants share one.
+var ansiCodes = map[rune]string{
+	'0': "30", '8': "30",
+	'1': "34", '9': "34",
+	'2': "32", 'a': "32",
+	'3': "36", 'b': "36",
+	'4': "31", 'c': "31",
+	'5': "35", 'd': "35",
+	'6': "33", 'e': "33",
+	'7': "37", 'f': "37",
+	'l': "1",
+	'n': "4",
+	'r': "0",
+}
+
+// colorNames maps JSON text component colors to their § codes.
+var colorNames = map[string]rune{
+	"black": '0', "dark_blue": '1', "dark_green": '2', "dark_aqua": '3',
+	"dark_red": '4', "dark_purple": '5', "gold": '6', "gray": '7',
+	"dark_gray": '8', "blue": '9', "green": 'a', "aqua": 'b',
+	"red": 'c', "light_purple": 'd', "yellow": 'e', "white": 'f',
+}
+
+// textComponent is the subset of a JSON text component that affects how it looks.
+type textComponent struct {
+	Text      string          `json:"text"`
+	Color     string          `json:"color"`
+	Bold      bool            `json:"bold"`
+	Underline bool            `json:"underlined"`
+	Extra     []textComponent `json:"extra"`
+}
+
+func (c textComponent) style() string {
+	var style string
+	if code, ok := colorNames[c.Color]; ok {
+		style += "§" + string(code)
+	}
+	if c.Bold {
+		style += "§l"
+	}
+	if c.Underline {
+		style += "§n"
+	}
+	return style
+}
+
+// toSectionCodes flattens a component and its children into §-formatted text. Children
+// inherit the parent's style, which is restored after each of them.
+func (c textComponent) toSectionCodes() string {
+	style := c.style()
+	var sb strings.Builder
+	sb.WriteString(style + c.Text)
+	for _, extra := range c.Extra {
+		sb.WriteString(extra.toSectionCodes())
+		sb.WriteString("§r" + style)
+	}
+	return sb.String()
+}
+
+// ToANSI converts § codes and whole-line JSON text components into ANSI escapes, resetting at
+// the end of every line so blocks split between lines keep rendering. ok is false when text
+// has no formatting, so callers can keep sending it as is.
+func ToANSI(text string) (converted string, ok bool) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "{") {
+			var component textComponent
+			if json.Unmarshal([]byte(trimmed), &component) == nil {
+				line = component.toSectionCodes()
+			}
+		}
+		if !strings.ContainsRune(line, '§') {
+			continue
+		}
+		ok = true
+
+		var sb strings.Builder
+		runes := []rune(line)
+		for j := 0; j < len(runes); j++ {
+			if runes[j] != '§' || j+1 == len(runes) {
+				sb.WriteRune(runes[j])
+				continue
+			}
+			j++
+			if code, known := ansiCodes[runes[j]]; known {
+				sb.WriteString("\u001b[" + code + "m")
+			}
+		}
+		sb.WriteString(ansiReset)
+		lines[i] = sb.String()
+	}
+	return strings.Join(lines, "\n"), ok
+}
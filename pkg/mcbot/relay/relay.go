@@ -0,0 +1,255 @@
+// Package relay posts Minecraft server console output to Discord: batched into code blocks,
+// with formatting codes shown as ANSI colors, optionally one thread per server run, and
+// capped at a number of messages per minute so log storms don't hit rate limits.
+package relay
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+)
+
+// Options configures a Relay.
+type Options struct {
+	// ChannelID receives the logs, unless ConsoleChannelID is set
+	ChannelID string
+
+	// ConsoleChannelID, if set, gets a new thread per server run that is archived once the
+	// server shuts down
+	ConsoleChannelID string
+
+	// MessagesPerMinute caps the messages sent per minute (default 10). Lines that don't fit
+	// are sent as a single file attachment instead.
+	MessagesPerMinute int
+
+	// Interval is how long Run collects lines before sending them (default 4 seconds)
+	Interval time.Duration
+
+	// StartMarker and StopMarker are the log lines, or parts of them, that begin and end a
+	// server run (default: the vanilla startup and shutdown messages)
+	StartMarker string
+	StopMarker  string
+}
+
+// Relay sends console lines to Discord. It is safe to use from several goroutines.
+type Relay struct {
+	session *discordgo.Session
+	opts    Options
+
+	mu       sync.Mutex
+	threadID string // thread receiving the logs of the current server run
+	sends    []time.Time
+}
+
+// New creates a relay that posts through s.
+func New(s *discordgo.Session, opts Options) *Relay {
+	if opts.MessagesPerMinute <= 0 {
+		opts.MessagesPerMinute = 10
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 4 * time.Second
+	}
+	if opts.StartMarker == "" {
+		opts.StartMarker = "Starting minecraft server version"
+	}
+	if opts.StopMarker == "" {
+		opts.StopMarker = "All dimensions are saved"
+	}
+	return &Relay{session: s, opts: opts}
+}
+
+// Run relays lines in batches every Interval until lines is closed or ctx is done. handle,
+// if set, is called with each line as it arrives, before it is batched.
+func (r *Relay) Run(ctx context.Context, lines <-chan string, handle func(line string)) {
+	var batch []string
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				r.Send(batch)
+				return
+			}
+			if handle != nil {
+				handle(line)
+			}
+			batch = append(batch, line)
+		case <-ticker.C:
+			r.Send(batch)
+			batch = nil
+		case <-ctx.Done():
+			r.Send(batch)
+			return
+		}
+	}
+}
+
+// Send relays lines to the log channel or, with a console channel, into the thread of the
+// server run they belong to.
+func (r *Relay) Send(lines []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.opts.ConsoleChannelID == "" {
+		r.sendLogs(r.opts.ChannelID, lines)
+		return
+	}
+
+	var pending []string
+	for _, line := range lines {
+		if strings.Contains(line, r.opts.StartMarker) {
+			r.sendToConsoleThread(pending)
+			pending = nil
+			r.archiveConsoleThread()
+		}
+		pending = append(pending, line)
+		if strings.Contains(line, r.opts.StopMarker) {
+			r.sendToConsoleThread(pending)
+			pending = nil
+			r.archiveConsoleThread()
+		}
+	}
+	r.sendToConsoleThread(pending)
+}
+
+func (r *Relay) sendToConsoleThread(lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	if r.threadID == "" {
+		name := "Server run " + time.Now().Format("2006-01-02 15:04")
+		thread, err := r.session.ThreadStart(r.opts.ConsoleChannelID, name, discordgo.ChannelTypeGuildPublicThread, 10080)
+		if err != nil {
+			fmt.Println("Error creating console thread:", err)
+			r.sendLogs(r.opts.ConsoleChannelID, lines)
+			return
+		}
+		r.threadID = thread.ID
+	}
+	r.sendLogs(r.threadID, lines)
+}
+
+func (r *Relay) archiveConsoleThread() {
+	if r.threadID == "" {
+		return
+	}
+
+	archived := true
+	_, err := r.session.ChannelEdit(r.threadID, &discordgo.ChannelEdit{Archived: &archived})
+	if err != nil {
+		fmt.Println("Error archiving console thread:", err)
+	}
+	r.threadID = ""
+}
+
+// takeBudget reserves up to n sends in the last minute's budget and returns how many were granted.
+func (r *Relay) takeBudget(n int) int {
+	cutoff := time.Now().Add(-time.Minute)
+	for len(r.sends) > 0 && r.sends[0].Before(cutoff) {
+		r.sends = r.sends[1:]
+	}
+	granted := min(n, max(r.opts.MessagesPerMinute-len(r.sends), 0))
+	for i := 0; i < granted; i++ {
+		r.sends = append(r.sends, time.Now())
+	}
+	return granted
+}
+
+// block is a run of log lines and the code block messages they render to. A line longer
+// than a message is hard-wrapped, so one block can take several messages.
+type block struct {
+	lines    []string
+	messages []string
+}
+
+// blocks groups lines into code blocks, keeping every line in exactly one block.
+func blocks(lines []string) []block {
+	rendered, language := make([]string, len(lines)), ""
+	for i, line := range lines {
+		rendered[i] = line
+		if converted, ok := ToANSI(line); ok {
+			rendered[i], language = converted, "ansi"
+		}
+	}
+	limit := discordutil.MaxMessageLength - len("```"+language+"\n") - len("\n```")
+
+	var blocks []block
+	start, size := 0, 0
+	for i := 0; i <= len(lines); i++ {
+		n := 0
+		if i < len(lines) {
+			n = utf8.RuneCountInString(rendered[i])
+			if i > start {
+				n++ // the newline joining it to the previous line
+			}
+		}
+		if i > start && (i == len(lines) || size+n > limit) {
+			text := strings.Join(rendered[start:i], "\n")
+			blocks = append(blocks, block{lines: lines[start:i], messages: discordutil.CodeBlocks(text, language)})
+			start, size = i, 0
+			if i < len(lines) {
+				n = utf8.RuneCountInString(rendered[i])
+			}
+		}
+		size += n
+	}
+	return blocks
+}
+
+// sendLogs relays lines as code blocks within the per-minute budget. During log storms the
+// lines that don't fit are sent as a single file attachment instead of many messages, which
+// also counts against the budget. Once the budget is spent, lines are dropped.
+func (r *Relay) sendLogs(channelID string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	blocks := blocks(lines)
+	needed := 0
+	for _, block := range blocks {
+		needed += len(block.messages)
+	}
+
+	granted := r.takeBudget(needed)
+	if granted < needed {
+		// Keep one of the granted sends for the attachment
+		granted--
+	}
+
+	shown := 0
+	for _, block := range blocks {
+		if len(block.messages) > granted {
+			break
+		}
+		granted -= len(block.messages)
+		for _, message := range block.messages {
+			_, err := r.session.ChannelMessageSend(channelID, message)
+			if err != nil {
+				fmt.Println("Error sending log updates to Discord:", err)
+			}
+		}
+		shown += len(block.lines)
+	}
+	if shown == len(lines) {
+		return
+	}
+
+	suppressed := lines[shown:]
+	if granted < 0 {
+		fmt.Printf("Dropping %d log lines for Discord, the log message budget is spent\n", len(suppressed))
+		return
+	}
+	summary := fmt.Sprintf("…suppressed %d lines, attached as file", len(suppressed))
+	_, err := r.session.ChannelFileSendWithMessage(channelID, summary, "server.log", strings.NewReader(strings.Join(suppressed, "\n")))
+	if err != nil {
+		fmt.Println("Error sending suppressed log lines to Discord:", err)
+	}
+}
@@ -0,0 +1,66 @@
+package relay
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/hunterjsb/xn-mc/pkg/discordutil"
+)
+
+func TestBlocks(t *testing.T) {
+	many := make([]string, 300)
+	for i := range many {
+		many[i] = strings.Repeat("y", 30)
+	}
+	tests := []struct {
+		name     string
+		lines    []string
+		messages []int // per block
+	}{
+		{"one block", []string{"a", "b", "c"}, []int{1}},
+		{"long line keeps its own block", []string{"a", strings.Repeat("x", 5000), "b"}, []int{1, 3, 1}},
+		{"many short lines", many, []int{1, 1, 1, 1, 1}},
+		{"formatted lines", []string{"§cred", "plain"}, []int{1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blocks := blocks(test.lines)
+			var messages []int
+			var lines []string
+			for _, block := range blocks {
+				messages = append(messages, len(block.messages))
+				lines = append(lines, block.lines...)
+				for _, message := range block.messages {
+					if n := utf8.RuneCountInString(message); n > discordutil.MaxMessageLength {
+						t.Errorf("message of %d characters", n)
+					}
+				}
+			}
+			if strings.Join(lines, "\n") != strings.Join(test.lines, "\n") {
+				t.Errorf("blocks hold %d lines, want the %d given", len(lines), len(test.lines))
+			}
+			if len(messages) != len(test.messages) {
+				t.Fatalf("got messages per block %v, want %v", messages, test.messages)
+			}
+			for i := range messages {
+				if messages[i] != test.messages[i] {
+					t.Fatalf("got messages per block %v, want %v", messages, test.messages)
+				}
+			}
+		})
+	}
+}
+
+func TestTakeBudget(t *testing.T) {
+	r := New(nil, Options{MessagesPerMinute: 3})
+	if got := r.takeBudget(2); got != 2 {
+		t.Errorf("first take granted %d, want 2", got)
+	}
+	if got := r.takeBudget(5); got != 1 {
+		t.Errorf("second take granted %d, want 1", got)
+	}
+	if got := r.takeBudget(1); got != 0 {
+		t.Errorf("third take granted %d, want 0", got)
+	}
+}
@@ -0,0 +1,88 @@
+package mcbot
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Context is a prefix command being handled.
+type Context struct {
+	Session *discordgo.Session
+	Message *discordgo.MessageCreate
+
+	// Name is the command as typed, Args the fields after it and Raw everything after the prefix
+	Name string
+	Args []string
+	Raw  string
+}
+
+// Reply sends content to the channel the command came from.
+func (c *Context) Reply(content string) (*discordgo.Message, error) {
+	return c.Session.ChannelMessageSend(c.Message.ChannelID, content)
+}
+
+// HandlerFunc handles one command.
+type HandlerFunc func(c *Context)
+
+// Router dispatches prefix commands like "!status" to their handlers.
+type Router struct {
+	Prefix byte
+
+	// Allow, if set, decides which messages may run commands, e.g. only those in a bot channel
+	Allow func(m *discordgo.MessageCreate) bool
+
+	// NotFound, if set, handles commands nothing was registered for
+	NotFound HandlerFunc
+
+	commands map[string]HandlerFunc
+}
+
+func NewRouter(prefix byte) *Router {
+	return &Router{Prefix: prefix, commands: map[string]HandlerFunc{}}
+}
+
+// Handle registers handler under each of names, replacing any earlier handler.
+func (r *Router) Handle(handler HandlerFunc, names ...string) {
+	for _, name := range names {
+		r.commands[name] = handler
+	}
+}
+
+// Handler returns the handler registered under name, or nil, so it can be wrapped.
+func (r *Router) Handler(name string) HandlerFunc {
+	return r.commands[name]
+}
+
+// Commands lists the registered command names in order.
+func (r *Router) Commands() []string {
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MessageCreate runs the command in m, if any. It can be passed to Session.AddHandler directly.
+func (r *Router) MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m.Author == nil || m.Author.ID == s.State.User.ID || m.Content == "" || m.Content[0] != r.Prefix {
+		return
+	}
+	if r.Allow != nil && !r.Allow(m) {
+		return
+	}
+	raw := m.Content[1:]
+	args := strings.Fields(raw)
+	if len(args) == 0 {
+		return
+	}
+
+	c := &Context{Session: s, Message: m, Name: args[0], Args: args[1:], Raw: raw}
+	if handler, ok := r.commands[c.Name]; ok {
+		handler(c)
+	} else if r.NotFound != nil {
+		r.NotFound(c)
+	}
+}
@@ -0,0 +1,42 @@
+package mcbot
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// ServerStats is what the hosting backend knows about the server.
+type ServerStats struct {
+	Running bool
+	Uptime  time.Duration
+	// MemoryKB is the resident memory of the server, if the backend reports it
+	MemoryKB int
+}
+
+// Server runs the Minecraft server on a hosting backend, such as those in the integrations
+// packages. Commands go through it rather than managing the server directly.
+type Server interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	// Restart stops the server, waits for it to exit and starts it again
+	Restart(ctx context.Context) error
+	Stats(ctx context.Context) (ServerStats, error)
+	// SendCommand runs a console command and returns its output, if the backend reports it
+	SendCommand(ctx context.Context, cmd string) (string, error)
+	// Logs streams new console lines until ctx is done
+	Logs(ctx context.Context) (<-chan string, error)
+}
+
+// Running asks server whether it is up. Errors count as down.
+func Running(ctx context.Context, server Server) bool {
+	stats, err := server.Stats(ctx)
+	return err == nil && stats.Running
+}
+
+var consoleColorRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// StripColors removes the ANSI colour codes some consoles add to their lines.
+func StripColors(line string) string {
+	return consoleColorRegex.ReplaceAllString(line, "")
+}
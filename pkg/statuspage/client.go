@@ -0,0 +1,110 @@
+// Package statuspage is a client for the Statuspage.io REST API.
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.statuspage.io/v1"
+
+var (
+	ErrUnauthorized = errors.New("statuspage: unauthorized")
+	ErrNotFound     = errors.New("statuspage: not found")
+	ErrRateLimited  = errors.New("statuspage: rate limited")
+)
+
+// APIError is returned for any non-2xx response. It matches ErrUnauthorized, ErrNotFound
+// and ErrRateLimited with errors.Is.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("statuspage: %d %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Client talks to a single Statuspage page.
+type Client struct {
+	APIKey  string
+	PageID  string
+	BaseURL string
+	HTTP    *http.Client
+}
+
+func NewClient(apiKey string, pageID string) *Client {
+	return &Client{
+		APIKey:  apiKey,
+		PageID:  pageID,
+		BaseURL: DefaultBaseURL,
+		HTTP:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// do sends a request for path under the page and decodes the JSON response into out, if not nil.
+func (c *Client) do(ctx context.Context, method string, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/pages/%s%s", c.BaseURL, c.PageID, path), reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "OAuth "+c.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Error   any    `json:"error"`
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		message := string(data)
+		if json.Unmarshal(data, &apiErr) == nil {
+			if apiErr.Message != "" {
+				message = apiErr.Message
+			} else if apiErr.Error != nil {
+				message = fmt.Sprint(apiErr.Error)
+			}
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: message}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
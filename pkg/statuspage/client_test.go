@@ -0,0 +1,283 @@
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// request is what the fixture server received.
+type request struct {
+	Method string
+	Path   string
+	Auth   string
+	Body   any
+}
+
+// newFixtureServer answers every request with status and the contents of testdata/fixture,
+// if set, and records the request.
+func newFixtureServer(t *testing.T, status int, fixture string) (*Client, *request) {
+	t.Helper()
+	var body []byte
+	if fixture != "" {
+		var err error
+		if body, err = os.ReadFile(filepath.Join("testdata", fixture)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := &request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Method, got.Path, got.Auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &got.Body); err != nil {
+				t.Errorf("request body is not JSON: %s", data)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("key", "page1")
+	client.BaseURL = server.URL
+	return client, got
+}
+
+// jsonValue decodes raw so it compares equal to a decoded request body.
+func jsonValue(t *testing.T, raw string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestRequests(t *testing.T) {
+	at := time.Unix(1714638600, 0)
+	tests := []struct {
+		name     string
+		fixture  string
+		call     func(c *Client) (any, error)
+		method   string
+		path     string
+		body     string
+		wantResp any
+	}{
+		{
+			name:    "set component status",
+			fixture: "component.json",
+			call: func(c *Client) (any, error) {
+				return c.SetComponentStatus(context.Background(), "cmp1", StatusMajorOutage)
+			},
+			method: http.MethodPatch, path: "/pages/page1/components/cmp1",
+			body:     `{"component": {"status": "major_outage"}}`,
+			wantResp: "Minecraft server",
+		},
+		{
+			name:    "create incident",
+			fixture: "incident.json",
+			call: func(c *Client) (any, error) {
+				return c.CreateIncident(context.Background(), IncidentParams{
+					Name:       "Minecraft server outage",
+					Status:     IncidentInvestigating,
+					Body:       "The Minecraft server went down unexpectedly.",
+					Components: map[string]string{"cmp1": StatusMajorOutage},
+				})
+			},
+			method: http.MethodPost, path: "/pages/page1/incidents",
+			body: `{"incident": {"name": "Minecraft server outage", "status": "investigating",
+				"body": "The Minecraft server went down unexpectedly.", "components": {"cmp1": "major_outage"}}}`,
+			wantResp: "https://stspg.io/abc123",
+		},
+		{
+			name:    "resolve incident",
+			fixture: "incident.json",
+			call: func(c *Client) (any, error) {
+				return c.ResolveIncident(context.Background(), "inc1", "Back up.")
+			},
+			method: http.MethodPatch, path: "/pages/page1/incidents/inc1",
+			body:     `{"incident": {"status": "resolved", "body": "Back up."}}`,
+			wantResp: "https://stspg.io/abc123",
+		},
+		{
+			name:    "edit incident update",
+			fixture: "incident_update.json",
+			call: func(c *Client) (any, error) {
+				return c.EditIncidentUpdate(context.Background(), "inc1", "upd1", "Corrected body")
+			},
+			method: http.MethodPatch, path: "/pages/page1/incidents/inc1/incident_updates/upd1",
+			body:     `{"incident_update": {"body": "Corrected body"}}`,
+			wantResp: "Corrected body",
+		},
+		{
+			name:    "list metrics",
+			fixture: "metrics.json",
+			call: func(c *Client) (any, error) {
+				return c.ListMetrics(context.Background())
+			},
+			method: http.MethodGet, path: "/pages/page1/metrics",
+			wantResp: "Players online",
+		},
+		{
+			name: "add metric data point",
+			call: func(c *Client) (any, error) {
+				return nil, c.AddMetricDataPoint(context.Background(), "met1", at, 7)
+			},
+			method: http.MethodPost, path: "/pages/page1/metrics/met1/data",
+			body: `{"data": {"timestamp": 1714638600, "value": 7}}`,
+		},
+		{
+			name:    "create subscriber",
+			fixture: "subscriber.json",
+			call: func(c *Client) (any, error) {
+				return c.CreateSubscriber(context.Background(), Subscriber{Email: "player@example.com", Components: []string{"cmp1"}})
+			},
+			method: http.MethodPost, path: "/pages/page1/subscribers",
+			body:     `{"subscriber": {"email": "player@example.com", "component_ids": ["cmp1"]}}`,
+			wantResp: "player@example.com",
+		},
+		{
+			name: "delete subscriber",
+			call: func(c *Client) (any, error) {
+				return nil, c.DeleteSubscriber(context.Background(), "sub1")
+			},
+			method: http.MethodDelete, path: "/pages/page1/subscribers/sub1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := http.StatusOK
+			if tt.fixture == "" {
+				status = http.StatusNoContent
+			}
+			client, got := newFixtureServer(t, status, tt.fixture)
+
+			resp, err := tt.call(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Method != tt.method || got.Path != tt.path {
+				t.Errorf("sent %s %s, want %s %s", got.Method, got.Path, tt.method, tt.path)
+			}
+			if got.Auth != "OAuth key" {
+				t.Errorf("sent Authorization %q", got.Auth)
+			}
+			var wantBody any
+			if tt.body != "" {
+				wantBody = jsonValue(t, tt.body)
+			}
+			if !reflect.DeepEqual(got.Body, wantBody) {
+				t.Errorf("sent body %v, want %v", got.Body, wantBody)
+			}
+
+			// Spot check one decoded field of the fixture
+			var field any
+			switch resp := resp.(type) {
+			case Component:
+				field = resp.Name
+			case Incident:
+				field = resp.Shortlink
+			case IncidentUpdate:
+				field = resp.Body
+			case Subscriber:
+				field = resp.Email
+			case []Metric:
+				field = resp[0].Name
+			}
+			if field != tt.wantResp {
+				t.Errorf("decoded %v, want %v", field, tt.wantResp)
+			}
+		})
+	}
+}
+
+func TestIncidentDecoding(t *testing.T) {
+	client, _ := newFixtureServer(t, http.StatusOK, "incident.json")
+	incident, err := client.GetIncident(context.Background(), "inc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if incident.ResolvedAt != nil || incident.CreatedAt == nil || !incident.CreatedAt.Equal(time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("decoded times resolved %v created %v", incident.ResolvedAt, incident.CreatedAt)
+	}
+	if len(incident.IncidentUpdates) != 1 || incident.IncidentUpdates[0].IncidentID != "inc1" {
+		t.Errorf("decoded updates %+v", incident.IncidentUpdates)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		status      int
+		fixture     string
+		wantMessage string
+		is          error
+		isNot       []error
+	}{
+		{http.StatusUnauthorized, "error_unauthorized.json", "Could not authenticate", ErrUnauthorized, []error{ErrNotFound, ErrRateLimited}},
+		{http.StatusForbidden, "error_unauthorized.json", "Could not authenticate", ErrUnauthorized, []error{ErrNotFound}},
+		{http.StatusNotFound, "error_not_found.json", "Incident not found", ErrNotFound, []error{ErrUnauthorized, ErrRateLimited}},
+		{http.StatusTooManyRequests, "", "", ErrRateLimited, []error{ErrNotFound}},
+		{http.StatusInternalServerError, "", "", nil, []error{ErrUnauthorized, ErrNotFound, ErrRateLimited}},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			client, _ := newFixtureServer(t, tt.status, tt.fixture)
+			_, err := client.GetIncident(context.Background(), "inc1")
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("got %v, want an *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != tt.wantMessage {
+				t.Errorf("got %d %q, want %d %q", apiErr.StatusCode, apiErr.Message, tt.status, tt.wantMessage)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.is)
+			}
+			for _, target := range tt.isNot {
+				if errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = true", err, target)
+				}
+			}
+		})
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	client := NewClient("key", "page1")
+	client.BaseURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.ListComponents(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %s after its context expired", elapsed)
+	}
+}
@@ -0,0 +1,93 @@
+package statuspage
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Component statuses accepted by Statuspage.
+const (
+	StatusOperational         = "operational"
+	StatusDegradedPerformance = "degraded_performance"
+	StatusPartialOutage       = "partial_outage"
+	StatusMajorOutage         = "major_outage"
+	StatusUnderMaintenance    = "under_maintenance"
+)
+
+type Component struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	GroupID     string     `json:"group_id,omitempty"`
+	Group       bool       `json:"group,omitempty"`
+	Position    int        `json:"position,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type ComponentGroup struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Components  []string   `json:"components,omitempty"`
+	Position    int        `json:"position,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+func (c *Client) ListComponents(ctx context.Context) ([]Component, error) {
+	var res []Component
+	err := c.do(ctx, http.MethodGet, "/components", nil, &res)
+	return res, err
+}
+
+func (c *Client) GetComponent(ctx context.Context, id string) (Component, error) {
+	var res Component
+	err := c.do(ctx, http.MethodGet, "/components/"+id, nil, &res)
+	return res, err
+}
+
+func (c *Client) CreateComponent(ctx context.Context, component Component) (Component, error) {
+	var res Component
+	err := c.do(ctx, http.MethodPost, "/components", map[string]Component{"component": component}, &res)
+	return res, err
+}
+
+// UpdateComponent patches the non-zero fields of component.
+func (c *Client) UpdateComponent(ctx context.Context, id string, component Component) (Component, error) {
+	var res Component
+	err := c.do(ctx, http.MethodPatch, "/components/"+id, map[string]Component{"component": component}, &res)
+	return res, err
+}
+
+func (c *Client) SetComponentStatus(ctx context.Context, id string, status string) (Component, error) {
+	return c.UpdateComponent(ctx, id, Component{Status: status})
+}
+
+func (c *Client) DeleteComponent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/components/"+id, nil, nil)
+}
+
+func (c *Client) ListComponentGroups(ctx context.Context) ([]ComponentGroup, error) {
+	var res []ComponentGroup
+	err := c.do(ctx, http.MethodGet, "/component-groups", nil, &res)
+	return res, err
+}
+
+func (c *Client) CreateComponentGroup(ctx context.Context, group ComponentGroup) (ComponentGroup, error) {
+	var res ComponentGroup
+	err := c.do(ctx, http.MethodPost, "/component-groups", map[string]ComponentGroup{"component_group": group}, &res)
+	return res, err
+}
+
+func (c *Client) UpdateComponentGroup(ctx context.Context, id string, group ComponentGroup) (ComponentGroup, error) {
+	var res ComponentGroup
+	err := c.do(ctx, http.MethodPatch, "/component-groups/"+id, map[string]ComponentGroup{"component_group": group}, &res)
+	return res, err
+}
+
+func (c *Client) DeleteComponentGroup(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/component-groups/"+id, nil, nil)
+}
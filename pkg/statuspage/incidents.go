@@ -0,0 +1,108 @@
+package statuspage
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Incident statuses. The scheduled ones apply to maintenance incidents.
+const (
+	IncidentInvestigating = "investigating"
+	IncidentIdentified    = "identified"
+	IncidentMonitoring    = "monitoring"
+	IncidentResolved      = "resolved"
+	IncidentScheduled     = "scheduled"
+	IncidentInProgress    = "in_progress"
+	IncidentVerifying     = "verifying"
+	IncidentCompleted     = "completed"
+)
+
+type Incident struct {
+	ID              string           `json:"id,omitempty"`
+	Name            string           `json:"name,omitempty"`
+	Status          string           `json:"status,omitempty"`
+	Impact          string           `json:"impact,omitempty"`
+	Shortlink       string           `json:"shortlink,omitempty"`
+	IncidentUpdates []IncidentUpdate `json:"incident_updates,omitempty"`
+	ScheduledFor    *time.Time       `json:"scheduled_for,omitempty"`
+	ScheduledUntil  *time.Time       `json:"scheduled_until,omitempty"`
+	ResolvedAt      *time.Time       `json:"resolved_at,omitempty"`
+	CreatedAt       *time.Time       `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time       `json:"updated_at,omitempty"`
+}
+
+type IncidentUpdate struct {
+	ID         string     `json:"id,omitempty"`
+	IncidentID string     `json:"incident_id,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Body       string     `json:"body,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// IncidentParams creates or updates an incident. Components maps component IDs to the
+// status they should be set to alongside the incident.
+type IncidentParams struct {
+	Name                        string            `json:"name,omitempty"`
+	Status                      string            `json:"status,omitempty"`
+	Body                        string            `json:"body,omitempty"`
+	ImpactOverride              string            `json:"impact_override,omitempty"`
+	ComponentIDs                []string          `json:"component_ids,omitempty"`
+	Components                  map[string]string `json:"components,omitempty"`
+	TemplateID                  string            `json:"template_id,omitempty"`
+	DeliverNotifications        *bool             `json:"deliver_notifications,omitempty"`
+	ScheduledFor                *time.Time        `json:"scheduled_for,omitempty"`
+	ScheduledUntil              *time.Time        `json:"scheduled_until,omitempty"`
+	ScheduledRemindPrior        bool              `json:"scheduled_remind_prior,omitempty"`
+	ScheduledAutoInProgress     bool              `json:"scheduled_auto_in_progress,omitempty"`
+	ScheduledAutoCompleted      bool              `json:"scheduled_auto_completed,omitempty"`
+	AutoTransitionToOperational bool              `json:"auto_transition_to_operational_state,omitempty"`
+}
+
+func (c *Client) ListIncidents(ctx context.Context) ([]Incident, error) {
+	var res []Incident
+	err := c.do(ctx, http.MethodGet, "/incidents", nil, &res)
+	return res, err
+}
+
+func (c *Client) ListUnresolvedIncidents(ctx context.Context) ([]Incident, error) {
+	var res []Incident
+	err := c.do(ctx, http.MethodGet, "/incidents/unresolved", nil, &res)
+	return res, err
+}
+
+func (c *Client) GetIncident(ctx context.Context, id string) (Incident, error) {
+	var res Incident
+	err := c.do(ctx, http.MethodGet, "/incidents/"+id, nil, &res)
+	return res, err
+}
+
+func (c *Client) CreateIncident(ctx context.Context, params IncidentParams) (Incident, error) {
+	var res Incident
+	err := c.do(ctx, http.MethodPost, "/incidents", map[string]IncidentParams{"incident": params}, &res)
+	return res, err
+}
+
+// UpdateIncident patches an incident. Setting Status and Body posts a new incident update.
+func (c *Client) UpdateIncident(ctx context.Context, id string, params IncidentParams) (Incident, error) {
+	var res Incident
+	err := c.do(ctx, http.MethodPatch, "/incidents/"+id, map[string]IncidentParams{"incident": params}, &res)
+	return res, err
+}
+
+func (c *Client) ResolveIncident(ctx context.Context, id string, body string) (Incident, error) {
+	return c.UpdateIncident(ctx, id, IncidentParams{Status: IncidentResolved, Body: body})
+}
+
+func (c *Client) DeleteIncident(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/incidents/"+id, nil, nil)
+}
+
+// EditIncidentUpdate changes the body of an existing incident update.
+func (c *Client) EditIncidentUpdate(ctx context.Context, incidentID string, updateID string, body string) (IncidentUpdate, error) {
+	var res IncidentUpdate
+	payload := map[string]IncidentUpdate{"incident_update": {Body: body}}
+	err := c.do(ctx, http.MethodPatch, "/incidents/"+incidentID+"/incident_updates/"+updateID, payload, &res)
+	return res, err
+}
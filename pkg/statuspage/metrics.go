@@ -0,0 +1,36 @@
+package statuspage
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type Metric struct {
+	ID            string     `json:"id,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	Suffix        string     `json:"suffix,omitempty"`
+	Display       bool       `json:"display,omitempty"`
+	DecimalPlaces int        `json:"decimal_places,omitempty"`
+	YAxisMin      float64    `json:"y_axis_min,omitempty"`
+	YAxisMax      float64    `json:"y_axis_max,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+func (c *Client) ListMetrics(ctx context.Context) ([]Metric, error) {
+	var res []Metric
+	err := c.do(ctx, http.MethodGet, "/metrics", nil, &res)
+	return res, err
+}
+
+// AddMetricDataPoint submits a single value for a metric at the given time.
+func (c *Client) AddMetricDataPoint(ctx context.Context, metricID string, at time.Time, value float64) error {
+	payload := map[string]any{"data": map[string]any{"timestamp": at.Unix(), "value": value}}
+	return c.do(ctx, http.MethodPost, "/metrics/"+metricID+"/data", payload, nil)
+}
+
+// DeleteMetricData removes every data point of a metric.
+func (c *Client) DeleteMetricData(ctx context.Context, metricID string) error {
+	return c.do(ctx, http.MethodDelete, "/metrics/"+metricID+"/data", nil, nil)
+}
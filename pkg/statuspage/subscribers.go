@@ -0,0 +1,34 @@
+package statuspage
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type Subscriber struct {
+	ID                           string     `json:"id,omitempty"`
+	Email                        string     `json:"email,omitempty"`
+	Endpoint                     string     `json:"endpoint,omitempty"`
+	Mode                         string     `json:"mode,omitempty"`
+	Components                   []string   `json:"component_ids,omitempty"`
+	SkipConfirmationNotification bool       `json:"skip_confirmation_notification,omitempty"`
+	CreatedAt                    *time.Time `json:"created_at,omitempty"`
+}
+
+func (c *Client) ListSubscribers(ctx context.Context) ([]Subscriber, error) {
+	var res []Subscriber
+	err := c.do(ctx, http.MethodGet, "/subscribers", nil, &res)
+	return res, err
+}
+
+// CreateSubscriber subscribes an email address or, with Endpoint set, a webhook.
+func (c *Client) CreateSubscriber(ctx context.Context, subscriber Subscriber) (Subscriber, error) {
+	var res Subscriber
+	err := c.do(ctx, http.MethodPost, "/subscribers", map[string]Subscriber{"subscriber": subscriber}, &res)
+	return res, err
+}
+
+func (c *Client) DeleteSubscriber(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/subscribers/"+id, nil, nil)
+}